@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/workflow/action"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+func newMatrixCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   "matrix <workflow> [job]",
+		Short: "Print the job combinations generated by matrix strategies",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+
+			definition, err := container.WorkflowManager().GetWorkflowDefinition(args[0])
+			if err != nil {
+				return err
+			}
+
+			jobs := definition.GetJobs()
+			jobNames := []string{}
+			if len(args) > 1 {
+				jobName := args[1]
+				if _, ok := jobs[jobName]; !ok {
+					return fmt.Errorf("unknown job %q in workflow %q", jobName, definition.Name)
+				}
+				if workflow.GetJobMatrix(jobs[jobName]) == nil {
+					return fmt.Errorf("job %q in workflow %q has no matrix strategy", jobName, definition.Name)
+				}
+				jobNames = append(jobNames, jobName)
+			} else {
+				for jobName, job := range jobs {
+					if workflow.GetJobMatrix(job) != nil {
+						jobNames = append(jobNames, jobName)
+					}
+				}
+				sort.Strings(jobNames)
+			}
+
+			if len(jobNames) == 0 {
+				container.Logger().Printfln("No matrix strategies found in %s", definition.Name)
+				return nil
+			}
+
+			for _, jobName := range jobNames {
+				printMatrix(container, jobName, workflow.GetJobMatrix(jobs[jobName]))
+			}
+			return nil
+		},
+	}
+}
+
+func printMatrix(container *action.Container, jobName string, matrix interface{}) {
+	logger := container.Logger()
+	styles := container.Styles()
+
+	expansion, err := workflow.ExpandMatrix(matrix)
+	if err != nil {
+		logger.Printfln("%s: %s", styles.Bold(jobName), err)
+		return
+	}
+
+	logger.Printfln("%s (%d jobs)", styles.Bold(jobName), len(expansion.Combinations))
+	table := tablewriter.NewWriter(logger)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeader(expansion.Keys)
+	for _, combination := range expansion.Combinations {
+		row := []string{}
+		for _, key := range expansion.Keys {
+			row = append(row, formatMatrixValue(combination[key]))
+		}
+		table.Append(row)
+	}
+	table.Render()
+
+	if len(expansion.Combinations) > workflow.MaxMatrixJobs {
+		logger.Printfln("  %s matrix exceeds the limit of %d jobs", styles.StyleWarning("Warning:"), workflow.MaxMatrixJobs)
+	}
+	for _, exclude := range expansion.UnmatchedExcludes {
+		logger.Printfln("  %s exclude %s doesn't match any combination", styles.StyleWarning("Warning:"), workflow.FormatMatrixValues(exclude))
+	}
+}
+
+func formatMatrixValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		json, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return string(json)
+	}
+}
@@ -71,6 +71,8 @@ func NewRootCommand(containerFunc ContainerBuilderFunc) *cobra.Command {
 	cmd.AddCommand(newCheckWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newWatchWorkflowsCmd(containerFunc))
 	cmd.AddCommand(newImportWorkflowsCmd(containerFunc))
+	cmd.AddCommand(newConvertWorkflowsCmd(containerFunc))
+	cmd.AddCommand(newMatrixCmd(containerFunc))
 	cmd.AddCommand(newInitCmd(containerFunc))
 	cmd.AddCommand(newVersionCmd(containerFunc))
 
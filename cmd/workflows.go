@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/jbrunton/gflows/workflow"
 	"github.com/jbrunton/gflows/workflow/action"
 	"github.com/olekukonko/tablewriter"
 	"github.com/thoas/go-funk"
@@ -11,11 +15,37 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// scheduleRunCount - the number of upcoming runs to list for scheduled workflows
+const scheduleRunCount = 3
+
+type workflowListItem struct {
+	Name        string                     `json:"name"`
+	Source      string                     `json:"source"`
+	Destination string                     `json:"destination"`
+	Status      string                     `json:"status"`
+	Triggers    []string                   `json:"triggers"`
+	Schedules   []workflow.ScheduleSummary `json:"schedules"`
+	Diagnostics []workflow.Diagnostic      `json:"diagnostics,omitempty"`
+}
+
 func newListWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List workflows",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			showTriggers, err := cmd.Flags().GetBool("triggers")
+			if err != nil {
+				return err
+			}
+
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+			if !funk.ContainsString([]string{"table", "json"}, format) {
+				return fmt.Errorf("Unexpected format: %q, valid options are table or json", format)
+			}
+
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
@@ -28,75 +58,136 @@ func newListWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 			}
 			validator := container.Validator()
 
-			table := tablewriter.NewWriter(container.Logger())
-			table.SetHeader([]string{"Name", "Source", "Target", "Status"})
-			context := container.Context()
+			items := []workflowListItem{}
+			now := time.Now()
 			for _, definition := range definitions {
-				colors := []tablewriter.Colors{
-					tablewriter.Colors{},
-					tablewriter.Colors{},
-					tablewriter.Colors{},
-					tablewriter.Colors{},
+				items = append(items, workflowListItem{
+					Name:        definition.Name,
+					Source:      definition.Source,
+					Destination: definition.Destination,
+					Status:      getWorkflowStatus(validator, definition),
+					Triggers:    definition.GetTriggers(),
+					Schedules:   definition.GetScheduleSummaries(now, scheduleRunCount),
+					Diagnostics: definition.GetDiagnostics(),
+				})
+			}
+
+			if format == "json" {
+				json, err := json.MarshalIndent(items, "", "  ")
+				if err != nil {
+					return err
 				}
+				container.Logger().Println(string(json))
+				return nil
+			}
+
+			table := tablewriter.NewWriter(container.Logger())
+			header := []string{"Name", "Source", "Target", "Status"}
+			if showTriggers {
+				header = append(header, "Triggers")
+				table.SetAutoWrapText(false)
+			}
+			table.SetHeader(header)
+			context := container.Context()
+			for _, item := range items {
+				colors := make([]tablewriter.Colors, len(header))
 				if context.EnableColors {
 					colors[0] = tablewriter.Colors{tablewriter.FgGreenColor}
 					colors[1] = tablewriter.Colors{tablewriter.FgYellowColor}
 					colors[2] = tablewriter.Colors{tablewriter.FgYellowColor}
-				}
-				var status string
-				if !definition.Status.Valid {
-					status = "TEMPLATE ERROR"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else if !validator.ValidateSchema(definition).Valid {
-					status = "INVALID SCHEMA"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else if !validator.ValidateContent(definition).Valid {
-					status = "OUT OF DATE"
-					if context.EnableColors {
-						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
-					}
-				} else {
-					status = "UP TO DATE"
-					if context.EnableColors {
+					if item.Status == "UP TO DATE" {
 						colors[3] = tablewriter.Colors{tablewriter.FgGreenColor}
+					} else {
+						colors[3] = tablewriter.Colors{tablewriter.FgRedColor}
 					}
 				}
 
-				row := []string{definition.Name, definition.Source, definition.Destination, status}
+				row := []string{item.Name, item.Source, item.Destination, item.Status}
+				if showTriggers {
+					row = append(row, formatTriggers(item))
+				}
 				table.Rich(row, colors)
 			}
 			table.Render()
 			return nil
 		},
 	}
+	cmd.Flags().Bool("triggers", false, "show the events which trigger each workflow, including upcoming scheduled runs")
+	cmd.Flags().String("format", "table", "the output format (either table or json)")
+	addDataValueFlag(cmd)
+	return cmd
+}
+
+func getWorkflowStatus(validator *workflow.Validator, definition *workflow.Definition) string {
+	if !definition.Status.Valid {
+		return "TEMPLATE ERROR"
+	}
+	if !validator.ValidateSchema(definition).Valid {
+		return "INVALID SCHEMA"
+	}
+	contentResult := validator.ValidateContent(definition)
+	if contentResult.HandEdited {
+		return "MODIFIED"
+	}
+	if !contentResult.Valid {
+		return "OUT OF DATE"
+	}
+	return "UP TO DATE"
+}
+
+func formatTriggers(item workflowListItem) string {
+	lines := []string{}
+	for _, trigger := range item.Triggers {
+		lines = append(lines, trigger)
+		if trigger != "schedule" {
+			continue
+		}
+		for _, schedule := range item.Schedules {
+			if schedule.Error != "" {
+				lines = append(lines, fmt.Sprintf("  %s: %s", schedule.Cron, schedule.Error))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %s", schedule.Cron, schedule.Description))
+			nextRuns := []string{}
+			for _, run := range schedule.NextRuns {
+				nextRuns = append(nextRuns, run.Format("2006-01-02 15:04"))
+			}
+			lines = append(lines, fmt.Sprintf("    next: %s", strings.Join(nextRuns, ", ")))
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 func newUpdateWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Updates workflow files",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			force, err := cmd.Flags().GetBool("force")
+			if err != nil {
+				return err
+			}
+
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
 			}
 			workflowManager := container.WorkflowManager()
-			err = workflowManager.UpdateWorkflows()
+			err = workflowManager.UpdateWorkflows(force)
 			if err != nil {
 				return err
 			}
 			return nil
 		},
 	}
+	cmd.Flags().BoolP("force", "f", false, "overwrite workflows which have been modified since they were generated")
+	addDataValueFlag(cmd)
+	return cmd
 }
 
 func newInitCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "init --engine <ytt|jsonnet>",
+		Use:   "init --engine <ytt|jsonnet|cue|gotemplate|yaml>",
 		Short: "Setup config and templates for first time use using the given template engine",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			engine, err := cmd.Flags().GetString("engine")
@@ -106,8 +197,8 @@ func newInitCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 			if engine == "" {
 				return errors.New("--engine flag required")
 			}
-			if !funk.ContainsString([]string{"jsonnet", "ytt"}, engine) {
-				return fmt.Errorf("Unexpected engine name: %q, valid options are ytt or jsonnet", engine)
+			if !funk.ContainsString([]string{"jsonnet", "ytt", "cue", "gotemplate", "yaml"}, engine) {
+				return fmt.Errorf("Unexpected engine name: %q, valid options are ytt, jsonnet, cue, gotemplate or yaml", engine)
 			}
 
 			workflowName, err := cmd.Flags().GetString("workflow-name")
@@ -129,7 +220,7 @@ func newInitCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 			return nil
 		},
 	}
-	cmd.Flags().String("engine", "", "the template engine to use (either jsonnet or ytt)")
+	cmd.Flags().String("engine", "", "the template engine to use (jsonnet, ytt, cue, gotemplate or yaml)")
 	cmd.Flags().String("workflow-name", "gflows", "the name of the workflow to generate")
 	cmd.Flags().String("github-dir", ".github", "the relative path to the .github directory")
 	cmd.Flags().String("config-path", ".gflows/config.yml", "the relative path to the gflows config.yml file")
@@ -180,6 +271,7 @@ func newCheckWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 	}
 	cmd.Flags().BoolP("watch", "w", false, "watch workflow templates for changes")
 	cmd.Flags().Bool("show-diffs", false, "show diff with generated workflow (useful when refactoring)")
+	addDataValueFlag(cmd)
 	return cmd
 }
 
@@ -201,21 +293,54 @@ func newWatchWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
 			return nil
 		},
 	}
+	addDataValueFlag(cmd)
 	return cmd
 }
 
+// addDataValueFlag - adds the --data-value flag to commands which generate workflows
+func addDataValueFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("data-value", nil, "set a ytt data value, as a string (format: key.subkey=value) (can be specified multiple times)")
+}
+
 func newImportWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import existing workflows",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			extractCommon, err := cmd.Flags().GetBool("extract-common")
+			if err != nil {
+				return err
+			}
 			container, err := containerFunc(cmd)
 			if err != nil {
 				return err
 			}
 			manager := container.WorkflowManager()
-			err = manager.ImportWorkflows()
+			err = manager.ImportWorkflows(extractCommon)
 			return err
 		},
 	}
+	cmd.Flags().Bool("extract-common", false, "extract steps and jobs repeated across workflows into a lib")
+	return cmd
+}
+
+func newConvertWorkflowsCmd(containerFunc ContainerBuilderFunc) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Convert templates to another engine",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			engineName, err := cmd.Flags().GetString("to")
+			if err != nil {
+				return err
+			}
+			container, err := containerFunc(cmd)
+			if err != nil {
+				return err
+			}
+			return container.Converter().ConvertWorkflows(engineName)
+		},
+	}
+	cmd.Flags().String("to", "", "the engine to convert templates to (jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)")
+	cmd.MarkFlagRequired("to")
+	return cmd
 }
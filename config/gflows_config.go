@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"sort"
+	"strings"
 
 	"github.com/jbrunton/gflows/io"
 	_ "github.com/jbrunton/gflows/static/statik"
@@ -13,6 +15,7 @@ import (
 	"github.com/thoas/go-funk"
 	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 // GFlowsConfig - type of current gflows context
@@ -26,6 +29,9 @@ type GFlowsConfig struct {
 		Engine    string
 		Defaults  GFlowsTemplateConfig
 		Overrides map[string]*GFlowsTemplateConfig
+		Engines   map[string]*GFlowsTemplateConfig
+		Plugins   map[string]*GFlowsPluginConfig
+		Checksums map[string]string
 	}
 }
 
@@ -37,13 +43,84 @@ type GFlowsWorkflowConfig struct {
 		}
 		Content struct {
 			Enabled *bool
+			Mode    string
+		}
+		Permissions struct {
+			Enabled *bool
+			Max     map[string]string
+		}
+		Matrix struct {
+			Enabled *bool
+		}
+		Schedule struct {
+			Enabled *bool
+		}
+		Inputs struct {
+			Enabled *bool
+		}
+		Calls struct {
+			Enabled *bool
+		}
+		Actions struct {
+			Enabled *bool
+		}
+		Shell struct {
+			Enabled *bool
+		}
+		Deprecations struct {
+			Enabled *bool
+			Strict  *bool
+		}
+		Steps struct {
+			Enabled *bool
 		}
 	}
 }
 
 type GFlowsTemplateConfig struct {
+	Engine       string
 	Libs         []string
 	Dependencies []string
+	DataValues   []string `yaml:"dataValues"`
+	Overlays     []string
+}
+
+// GFlowsPluginConfig - config for an external template engine, referred to as plugin:<name>
+type GFlowsPluginConfig struct {
+	Command    string
+	Args       []string
+	Extensions []string
+	Variables  map[string]string
+}
+
+// templateEngines - the names of the built in template engines
+var templateEngines = []string{"ytt", "jsonnet", "cue", "gotemplate", "yaml"}
+
+// PluginEnginePrefix - the prefix for the names of plugin engines
+const PluginEnginePrefix = "plugin:"
+
+// GetPluginConfig - returns the config for the given plugin engine name (e.g. "plugin:my-plugin"), or nil if it isn't
+// a configured plugin
+func (config *GFlowsConfig) GetPluginConfig(engineName string) *GFlowsPluginConfig {
+	if !strings.HasPrefix(engineName, PluginEnginePrefix) {
+		return nil
+	}
+	return config.Templates.Plugins[strings.TrimPrefix(engineName, PluginEnginePrefix)]
+}
+
+// IsValidEngine - returns true if the engine is a built in engine or a configured plugin
+func (config *GFlowsConfig) IsValidEngine(engineName string) bool {
+	return funk.ContainsString(templateEngines, engineName) || config.GetPluginConfig(engineName) != nil
+}
+
+// getPluginEngines - returns the names of configured plugin engines, sorted by name
+func (config *GFlowsConfig) getPluginEngines() []string {
+	engines := []string{}
+	for pluginName := range config.Templates.Plugins {
+		engines = append(engines, PluginEnginePrefix+pluginName)
+	}
+	sort.Strings(engines)
+	return engines
 }
 
 // LoadConfig - finds and returns the GFlowsConfig
@@ -100,8 +177,33 @@ func (config *GFlowsConfig) GetWorkflowBoolProperty(workflowName string, default
 	return defaultValue
 }
 
+// GetWorkflowMapProperty - returns the map given by the workflow override, if there is one, and otherwise the map
+// given by the workflow defaults. Maps aren't merged, so an override replaces the default entirely.
+func (config *GFlowsConfig) GetWorkflowMapProperty(workflowName string, selector func(config *GFlowsWorkflowConfig) map[string]string) map[string]string {
+	workflowConfig := config.Workflows.Overrides[workflowName]
+	if workflowConfig != nil {
+		value := selector(workflowConfig)
+		if value != nil {
+			return value
+		}
+	}
+	return selector(&config.Workflows.Defaults)
+}
+
 func (config *GFlowsConfig) GetTemplateArrayProperty(workflowName string, selector func(config *GFlowsTemplateConfig) []string) []string {
-	values := selector(&config.Templates.Defaults)
+	return config.GetEngineTemplateArrayProperty(config.Templates.Engine, workflowName, selector)
+}
+
+// GetEngineTemplateArrayProperty - returns the values given by the defaults for the engine, followed by those given by
+// the workflow override. The defaults for the default engine are given by templates.defaults, and those for any other
+// engine by templates.engines.
+func (config *GFlowsConfig) GetEngineTemplateArrayProperty(engineName string, workflowName string, selector func(config *GFlowsTemplateConfig) []string) []string {
+	values := []string{}
+	if engineName == config.Templates.Engine {
+		values = append(values, selector(&config.Templates.Defaults)...)
+	} else if engineConfig := config.Templates.Engines[engineName]; engineConfig != nil {
+		values = append(values, selector(engineConfig)...)
+	}
 	workflowConfig := config.Templates.Overrides[workflowName]
 	if workflowConfig != nil {
 		values = append(values, selector(workflowConfig)...)
@@ -109,36 +211,163 @@ func (config *GFlowsConfig) GetTemplateArrayProperty(workflowName string, select
 	return values
 }
 
-func (config *GFlowsConfig) GetAllLibs() []string {
-	libs := []string{}
-	libs = append(libs, config.Templates.Defaults.Libs...)
+// GetTemplateEngine - returns the engine given by the workflow override, if there is one, and otherwise the default
+// engine
+func (config *GFlowsConfig) GetTemplateEngine(workflowName string) string {
+	workflowConfig := config.Templates.Overrides[workflowName]
+	if workflowConfig != nil && workflowConfig.Engine != "" {
+		return workflowConfig.Engine
+	}
+	return config.Templates.Engine
+}
+
+// UsesTemplateEngine - returns true if the engine is the default engine or is given by any workflow override
+func (config *GFlowsConfig) UsesTemplateEngine(engineName string) bool {
+	if config.Templates.Engine == engineName {
+		return true
+	}
 	for _, override := range config.Templates.Overrides {
-		libs = append(libs, override.Libs...)
+		if override != nil && override.Engine == engineName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTemplateEngines - returns the default engine, followed by any other engines configured in templates.engines or
+// the workflow overrides
+func (config *GFlowsConfig) GetTemplateEngines() []string {
+	engines := []string{config.Templates.Engine}
+	for _, engineName := range append(templateEngines, config.getPluginEngines()...) {
+		if engineName == config.Templates.Engine {
+			continue
+		}
+		if _, ok := config.Templates.Engines[engineName]; ok {
+			engines = append(engines, engineName)
+			continue
+		}
+		for _, override := range config.Templates.Overrides {
+			if override != nil && override.Engine == engineName {
+				engines = append(engines, engineName)
+				break
+			}
+		}
+	}
+	return engines
+}
+
+func (config *GFlowsConfig) GetAllLibs() []string {
+	return config.getAllTemplateArrayValues(func(config *GFlowsTemplateConfig) []string {
+		return config.Libs
+	})
+}
+
+// GetAllEngineLibs - returns the libs for the given engine across all workflows, i.e. those given by the defaults for
+// the engine and by the overrides for workflows which use it
+func (config *GFlowsConfig) GetAllEngineLibs(engineName string) []string {
+	values := config.GetEngineTemplateArrayProperty(engineName, "", func(config *GFlowsTemplateConfig) []string {
+		return config.Libs
+	})
+	workflowNames := []string{}
+	for workflowName := range config.Templates.Overrides {
+		workflowNames = append(workflowNames, workflowName)
+	}
+	sort.Strings(workflowNames)
+	for _, workflowName := range workflowNames {
+		override := config.Templates.Overrides[workflowName]
+		if override != nil && config.GetTemplateEngine(workflowName) == engineName {
+			values = append(values, override.Libs...)
+		}
 	}
-	return libs
+	return values
 }
 
 func (config *GFlowsConfig) GetAllDependencies() []string {
-	deps := []string{}
-	deps = append(deps, config.Templates.Defaults.Dependencies...)
+	return config.getAllTemplateArrayValues(func(config *GFlowsTemplateConfig) []string {
+		return config.Dependencies
+	})
+}
+
+func (config *GFlowsConfig) GetAllDataValues() []string {
+	return config.getAllTemplateArrayValues(func(config *GFlowsTemplateConfig) []string {
+		return config.DataValues
+	})
+}
+
+func (config *GFlowsConfig) GetAllOverlays() []string {
+	return config.getAllTemplateArrayValues(func(config *GFlowsTemplateConfig) []string {
+		return config.Overlays
+	})
+}
+
+func (config *GFlowsConfig) getAllTemplateArrayValues(selector func(config *GFlowsTemplateConfig) []string) []string {
+	values := []string{}
+	values = append(values, selector(&config.Templates.Defaults)...)
+	for _, engineName := range append(templateEngines, config.getPluginEngines()...) {
+		if engineConfig := config.Templates.Engines[engineName]; engineConfig != nil {
+			values = append(values, selector(engineConfig)...)
+		}
+	}
 	for _, override := range config.Templates.Overrides {
-		deps = append(deps, override.Dependencies...)
+		if override != nil {
+			values = append(values, selector(override)...)
+		}
 	}
-	return deps
+	return values
 }
 
-func (config *GFlowsConfig) GetTemplateLibs(workflowName string) []string {
-	return config.GetTemplateArrayProperty(workflowName, func(config *GFlowsTemplateConfig) []string {
+func (config *GFlowsConfig) GetTemplateLibs(engineName string, workflowName string) []string {
+	return config.GetEngineTemplateArrayProperty(engineName, workflowName, func(config *GFlowsTemplateConfig) []string {
 		return config.Libs
 	})
 }
 
-func (config *GFlowsConfig) GetTemplateDeps(workflowName string) []string {
-	return config.GetTemplateArrayProperty(workflowName, func(config *GFlowsTemplateConfig) []string {
+func (config *GFlowsConfig) GetTemplateDeps(engineName string, workflowName string) []string {
+	return config.GetEngineTemplateArrayProperty(engineName, workflowName, func(config *GFlowsTemplateConfig) []string {
 		return config.Dependencies
 	})
 }
 
+// GetTemplateDataValues - returns the ytt data values files for the workflow
+func (config *GFlowsConfig) GetTemplateDataValues(engineName string, workflowName string) []string {
+	return config.GetEngineTemplateArrayProperty(engineName, workflowName, func(config *GFlowsTemplateConfig) []string {
+		return config.DataValues
+	})
+}
+
+// GetTemplateOverlays - returns the ytt overlay files for the workflow
+func (config *GFlowsConfig) GetTemplateOverlays(engineName string, workflowName string) []string {
+	return config.GetEngineTemplateArrayProperty(engineName, workflowName, func(config *GFlowsTemplateConfig) []string {
+		return config.Overlays
+	})
+}
+
+// SetTemplateEngine - returns the config content with templates.engine set to the given engine. The rest of the config
+// (including its formatting and comments) is left as it is.
+func SetTemplateEngine(content string, engineName string) (string, error) {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(content), &document); err != nil {
+		return "", err
+	}
+	var engine *yamlv3.Node
+	if len(document.Content) > 0 {
+		engine = yamlutil.GetMappingValue(yamlutil.GetMappingValue(document.Content[0], "templates"), "engine")
+	}
+	if engine == nil || engine.Kind != yamlv3.ScalarNode {
+		return "", errors.New("missing value for config: templates.engine")
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	line := lines[engine.Line-1]
+	start := engine.Column - 1
+	end := start + len(engine.Value)
+	if engine.Style == yamlv3.DoubleQuotedStyle || engine.Style == yamlv3.SingleQuotedStyle {
+		end += 2
+	}
+	lines[engine.Line-1] = line[:start] + engineName + line[end:]
+	return strings.Join(lines, ""), nil
+}
+
 func parseConfig(input []byte) (*GFlowsConfig, error) {
 	config := GFlowsConfig{}
 	err := yaml.Unmarshal(input, &config)
@@ -152,8 +381,32 @@ func parseConfig(input []byte) (*GFlowsConfig, error) {
 	if config.Templates.Engine == "" {
 		return nil, errors.New("missing value for config: templates.engine")
 	}
-	if !funk.ContainsString([]string{"ytt", "jsonnet"}, config.Templates.Engine) {
-		return nil, fmt.Errorf("unexpected value for templates.engine config field: %q (expected jsonnet or ytt)", config.Templates.Engine)
+	if !config.IsValidEngine(config.Templates.Engine) {
+		return nil, fmt.Errorf("unexpected value for templates.engine config field: %q (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)", config.Templates.Engine)
+	}
+	for engineName := range config.Templates.Engines {
+		if !config.IsValidEngine(engineName) {
+			return nil, fmt.Errorf("unexpected key for templates.engines config field: %q (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)", engineName)
+		}
+	}
+	for workflowName, override := range config.Templates.Overrides {
+		if override != nil && override.Engine != "" && !config.IsValidEngine(override.Engine) {
+			return nil, fmt.Errorf("unexpected value for templates.overrides.%s.engine config field: %q (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)", workflowName, override.Engine)
+		}
+	}
+	for pluginName, plugin := range config.Templates.Plugins {
+		if plugin == nil || plugin.Command == "" {
+			return nil, fmt.Errorf("missing value for config: templates.plugins.%s.command", pluginName)
+		}
+	}
+	if engines := config.GetTemplateEngines(); len(engines) > 1 {
+		// Without extensions a plugin would claim every template, including those for the other engines
+		for _, engineName := range engines {
+			plugin := config.GetPluginConfig(engineName)
+			if plugin != nil && len(plugin.Extensions) == 0 {
+				return nil, fmt.Errorf("missing value for config: templates.plugins.%s.extensions (required when more than one engine is used)", strings.TrimPrefix(engineName, PluginEnginePrefix))
+			}
+		}
 	}
 
 	return &config, nil
@@ -30,6 +30,112 @@ func TestGetTemplateArrayProperty(t *testing.T) {
 	}))
 }
 
+func TestGetEngineTemplateArrayProperty(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  defaults:",
+		"    libs: [vendor]",
+		"  engines:",
+		"    jsonnet:",
+		"      libs: [jsonnet-libs]",
+		"  overrides:",
+		"    my-workflow:",
+		"      engine: jsonnet",
+		"      libs: [my-lib]",
+	}, "\n")))
+	selector := func(config *GFlowsTemplateConfig) []string {
+		return config.Libs
+	}
+	assert.Equal(t, []string{"vendor"}, config.GetEngineTemplateArrayProperty("ytt", "some-workflow", selector))
+	assert.Equal(t, []string{"jsonnet-libs"}, config.GetEngineTemplateArrayProperty("jsonnet", "some-workflow", selector))
+	assert.Equal(t, []string{"jsonnet-libs", "my-lib"}, config.GetEngineTemplateArrayProperty("jsonnet", "my-workflow", selector))
+	assert.Equal(t, []string{}, config.GetEngineTemplateArrayProperty("cue", "some-workflow", selector))
+	assert.Equal(t, []string{"vendor", "jsonnet-libs", "my-lib"}, config.GetAllLibs())
+}
+
+func TestGetTemplateEngines(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  engines:",
+		"    yaml: {}",
+		"  overrides:",
+		"    my-workflow:",
+		"      engine: jsonnet",
+	}, "\n")))
+	assert.Equal(t, []string{"ytt", "jsonnet", "yaml"}, config.GetTemplateEngines())
+	assert.Equal(t, "jsonnet", config.GetTemplateEngine("my-workflow"))
+	assert.Equal(t, "ytt", config.GetTemplateEngine("other-workflow"))
+}
+
+func TestSetTemplateEngine(t *testing.T) {
+	content, err := SetTemplateEngine(strings.Join([]string{
+		"# gflows config",
+		"templates:",
+		"  overrides:",
+		"    my-workflow:",
+		"      engine: jsonnet",
+		"  engine: jsonnet # the default engine",
+		"",
+	}, "\n"), "ytt")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"# gflows config",
+		"templates:",
+		"  overrides:",
+		"    my-workflow:",
+		"      engine: jsonnet",
+		"  engine: ytt # the default engine",
+		"",
+	}, "\n"), content)
+
+	content, err = SetTemplateEngine(`templates: {engine: "ytt"}`, "jsonnet")
+	assert.NoError(t, err)
+	assert.Equal(t, `templates: {engine: jsonnet}`, content)
+
+	_, err = SetTemplateEngine("githubDir: .github", "ytt")
+	assert.EqualError(t, err, "missing value for config: templates.engine")
+}
+
+func TestParseConfigInvalidEngines(t *testing.T) {
+	_, err := parseConfig([]byte("templates:\n  engine: ytt\n  engines:\n    foo: {}"))
+	assert.EqualError(t, err, `unexpected key for templates.engines config field: "foo" (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)`)
+
+	_, err = parseConfig([]byte("templates:\n  engine: ytt\n  overrides:\n    my-workflow:\n      engine: foo"))
+	assert.EqualError(t, err, `unexpected value for templates.overrides.my-workflow.engine config field: "foo" (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)`)
+}
+
+func TestParseConfigPlugins(t *testing.T) {
+	config, err := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: plugin:my-plugin",
+		"  plugins:",
+		"    my-plugin:",
+		"      command: my-plugin",
+	}, "\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, &GFlowsPluginConfig{Command: "my-plugin"}, config.GetPluginConfig("plugin:my-plugin"))
+	assert.Nil(t, config.GetPluginConfig("my-plugin"))
+
+	_, err = parseConfig([]byte("templates:\n  engine: plugin:other-plugin"))
+	assert.EqualError(t, err, `unexpected value for templates.engine config field: "plugin:other-plugin" (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)`)
+
+	_, err = parseConfig([]byte("templates:\n  engine: ytt\n  plugins:\n    my-plugin: {}"))
+	assert.EqualError(t, err, "missing value for config: templates.plugins.my-plugin.command")
+
+	_, err = parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  engines:",
+		"    plugin:my-plugin: {}",
+		"  plugins:",
+		"    my-plugin:",
+		"      command: my-plugin",
+	}, "\n")))
+	assert.EqualError(t, err, "missing value for config: templates.plugins.my-plugin.extensions (required when more than one engine is used)")
+}
+
 func TestGetWorkflowBoolProperty(t *testing.T) {
 	config, _ := parseConfig([]byte(strings.Join([]string{
 		"templates:",
@@ -117,6 +223,32 @@ func TestGetWorkflowBoolProperty(t *testing.T) {
 	}
 }
 
+func TestGetWorkflowMapProperty(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  defaults:",
+		"    checks:",
+		"      permissions:",
+		"        max:",
+		"          contents: read",
+		"  overrides:",
+		"    my-workflow:",
+		"      checks:",
+		"        permissions:",
+		"          max:",
+		"            packages: write",
+	}, "\n")))
+
+	maxPermissionsSelector := func(config *GFlowsWorkflowConfig) map[string]string {
+		return config.Checks.Permissions.Max
+	}
+
+	assert.Equal(t, map[string]string{"contents": "read"}, config.GetWorkflowMapProperty("some-workflow", maxPermissionsSelector))
+	assert.Equal(t, map[string]string{"packages": "write"}, config.GetWorkflowMapProperty("my-workflow", maxPermissionsSelector))
+}
+
 func TestValidateConfig(t *testing.T) {
 	scenarios := []struct {
 		description    string
@@ -174,11 +306,19 @@ func TestValidateConfig(t *testing.T) {
 				"        uri: example.com",
 				"      content:",
 				"        enabled: true",
+				"        mode: semantic",
+				"      permissions:",
+				"        enabled: true",
+				"        max:",
+				"          contents: read",
 				"  overrides:",
 				"    my-workflow:",
 				"      checks:",
 				"        content:",
 				"          enabled: false",
+				"        permissions:",
+				"          max:",
+				"            contents: write",
 			}, "\n"),
 			expectedError:  "",
 			expectedOutput: "",
@@ -215,6 +355,31 @@ func TestGetAllLibs(t *testing.T) {
 	assert.Equal(t, []string{"my-lib", "my-other-lib"}, config.GetAllLibs())
 }
 
+func TestGetAllEngineLibs(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  defaults:",
+		"    libs:",
+		"    - ytt-lib",
+		"  engines:",
+		"    jsonnet:",
+		"      libs:",
+		"      - jsonnet-lib",
+		"  overrides:",
+		"    my-workflow:",
+		"      libs:",
+		"      - my-ytt-lib",
+		"    my-jsonnet-workflow:",
+		"      engine: jsonnet",
+		"      libs:",
+		"      - my-jsonnet-lib",
+	}, "\n")))
+
+	assert.Equal(t, []string{"ytt-lib", "my-ytt-lib"}, config.GetAllEngineLibs("ytt"))
+	assert.Equal(t, []string{"jsonnet-lib", "my-jsonnet-lib"}, config.GetAllEngineLibs("jsonnet"))
+}
+
 func TestGetAllDependencies(t *testing.T) {
 	config, _ := parseConfig([]byte(strings.Join([]string{
 		"templates:",
@@ -230,3 +395,23 @@ func TestGetAllDependencies(t *testing.T) {
 
 	assert.Equal(t, []string{"my-lib", "my-other-lib"}, config.GetAllDependencies())
 }
+
+func TestGetTemplateDataValuesAndOverlays(t *testing.T) {
+	config, _ := parseConfig([]byte(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  defaults:",
+		"    dataValues: [values/common.yml]",
+		"  overrides:",
+		"    my-workflow:",
+		"      dataValues: [values/my-workflow.yml]",
+		"      overlays: [overlays/timeout.yml]",
+	}, "\n")))
+
+	assert.Equal(t, []string{"values/common.yml"}, config.GetTemplateDataValues("ytt", "some-workflow"))
+	assert.Equal(t, []string{"values/common.yml", "values/my-workflow.yml"}, config.GetTemplateDataValues("ytt", "my-workflow"))
+	assert.Equal(t, []string{}, config.GetTemplateOverlays("ytt", "some-workflow"))
+	assert.Equal(t, []string{"overlays/timeout.yml"}, config.GetTemplateOverlays("ytt", "my-workflow"))
+	assert.Equal(t, []string{"values/common.yml", "values/my-workflow.yml"}, config.GetAllDataValues())
+	assert.Equal(t, []string{"overlays/timeout.yml"}, config.GetAllOverlays())
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,8 @@ type GFlowsContext struct {
 	GitHubDir    string
 	Config       *GFlowsConfig
 	EnableColors bool
+	// DataValues - ytt data values given on the command line (format: key=value)
+	DataValues []string
 }
 
 type ContextOpts struct {
@@ -30,6 +33,7 @@ type ContextOpts struct {
 	Debug          bool
 	Engine         string
 	AllowNoContext bool
+	DataValues     []string
 }
 
 func NewContext(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (*GFlowsContext, error) {
@@ -40,6 +44,10 @@ func NewContext(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (*GFlowsCo
 		return nil, err
 	}
 
+	if len(opts.DataValues) > 0 && !config.UsesTemplateEngine("ytt") {
+		return nil, errors.New("--data-value is only supported by the ytt engine, which isn't used by any workflows")
+	}
+
 	githubDir := config.GithubDir
 	if githubDir == "" {
 		githubDir = ".github/"
@@ -54,6 +62,7 @@ func NewContext(fs *afero.Afero, logger *io.Logger, opts ContextOpts) (*GFlowsCo
 		GitHubDir:    githubDir,
 		Dir:          contextDir,
 		EnableColors: opts.EnableColors,
+		DataValues:   opts.DataValues,
 	}
 
 	logger.Debugf("Creating context: %s\n", spew.Sdump(context))
@@ -97,6 +106,14 @@ func CreateContextOpts(cmd *cobra.Command) ContextOpts {
 		}
 	}
 
+	var dataValues []string
+	if cmd.Flags().Lookup("data-value") != nil {
+		dataValues, err = cmd.Flags().GetStringArray("data-value")
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	allowNoContext := funk.ContainsString([]string{"init", "version"}, cmd.Name())
 
 	return ContextOpts{
@@ -105,6 +122,7 @@ func CreateContextOpts(cmd *cobra.Command) ContextOpts {
 		Debug:          debug,
 		Engine:         engine,
 		AllowNoContext: allowNoContext,
+		DataValues:     dataValues,
 	}
 }
 
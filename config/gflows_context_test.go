@@ -55,6 +55,21 @@ func TestResolvePaths(t *testing.T) {
 	assert.Equal(t, []string{".gflows/foo"}, context.ResolvePaths([]string{"foo"}))
 }
 
+func TestNewContextWithDataValues(t *testing.T) {
+	fs := io.CreateMemFs()
+	logger := io.NewLogger(new(bytes.Buffer), false, false)
+	fs.WriteFile(".gflows/config.yml", []byte("templates:\n  engine: jsonnet\n"), 0644)
+	opts := ContextOpts{ConfigPath: ".gflows/config.yml", DataValues: []string{"foo=bar"}}
+
+	_, err := NewContext(fs, logger, opts)
+	assert.EqualError(t, err, "--data-value is only supported by the ytt engine, which isn't used by any workflows")
+
+	fs.WriteFile(".gflows/config.yml", []byte("templates:\n  engine: jsonnet\n  overrides:\n    deploy:\n      engine: ytt\n"), 0644)
+	context, err := NewContext(fs, logger, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo=bar"}, context.DataValues)
+}
+
 func TestCreateContextOpts(t *testing.T) {
 	scenarios := []struct {
 		description  string
@@ -40,11 +40,22 @@ func TestCheckCommand(t *testing.T) {
 func TestImportCommand(t *testing.T) {
 	runTests(t, "./tests/import/jsonnet/*.yml", true)
 	runTests(t, "./tests/import/ytt/*.yml", true)
+	runTests(t, "./tests/import/cue/*.yml", true)
+	runTests(t, "./tests/import/gotemplate/*.yml", true)
+	runTests(t, "./tests/import/yaml/*.yml", true)
+}
+
+func TestConvertCommand(t *testing.T) {
+	runTests(t, "./tests/convert/jsonnet/*.yml", true)
+	runTests(t, "./tests/convert/ytt/*.yml", false)
 }
 
 func TestInitCommand(t *testing.T) {
 	runTests(t, "./tests/init/jsonnet/*.yml", true)
 	runTests(t, "./tests/init/ytt/*.yml", true)
+	runTests(t, "./tests/init/cue/*.yml", true)
+	runTests(t, "./tests/init/gotemplate/*.yml", true)
+	runTests(t, "./tests/init/yaml/*.yml", true)
 	runTests(t, "./tests/init/errors/*.yml", true)
 }
 
@@ -53,9 +64,18 @@ func TestListCommand(t *testing.T) {
 	runTests(t, "./tests/ls/ytt/*.yml", true)
 }
 
+func TestMatrixCommand(t *testing.T) {
+	runTests(t, "./tests/matrix/*.yml", true)
+}
+
 func TestUpdateCommand(t *testing.T) {
 	runTests(t, "./tests/update/jsonnet/*.yml", true)
 	runTests(t, "./tests/update/ytt/*.yml", true)
+	runTests(t, "./tests/update/cue/*.yml", true)
+	runTests(t, "./tests/update/gotemplate/*.yml", true)
+	runTests(t, "./tests/update/yaml/*.yml", true)
+	runTests(t, "./tests/update/mixed/*.yml", false)
+	runTests(t, "./tests/update/plugin/*.yml", false)
 }
 
 func TestLocalLibs(t *testing.T) {
@@ -15,6 +15,7 @@ type GFlowsEnv struct {
 	installer *GFlowsLibInstaller
 	context   *config.GFlowsContext
 	logger    *io.Logger
+	engine    string
 }
 
 func NewGFlowsEnv(fs *afero.Afero, installer *GFlowsLibInstaller, context *config.GFlowsContext, logger *io.Logger) *GFlowsEnv {
@@ -24,6 +25,20 @@ func NewGFlowsEnv(fs *afero.Afero, installer *GFlowsLibInstaller, context *confi
 		installer: installer,
 		context:   context,
 		logger:    logger,
+		engine:    context.Config.Templates.Engine,
+	}
+}
+
+// ForEngine - returns an environment which shares dependencies with this one, but which returns lib paths for the
+// given engine
+func (env *GFlowsEnv) ForEngine(engineName string) *GFlowsEnv {
+	return &GFlowsEnv{
+		deps:      env.deps,
+		fs:        env.fs,
+		installer: env.installer,
+		context:   env.context,
+		logger:    env.logger,
+		engine:    engineName,
 	}
 }
 
@@ -61,10 +76,10 @@ func (env *GFlowsEnv) GetPackages() ([]pkg.GFlowsPackage, error) {
 }
 
 // GetLibPaths - returns search paths for the given workflow (including libs and local dependency
-// directories)
+// directories). The context libs directory is only included for the default engine.
 func (env *GFlowsEnv) GetLibPaths(workflowName string) ([]string, error) {
-	libPaths := env.context.Config.GetTemplateLibs(workflowName)
-	depPaths := env.context.Config.GetTemplateDeps(workflowName)
+	libPaths := env.context.Config.GetTemplateLibs(env.engine, workflowName)
+	depPaths := env.context.Config.GetTemplateDeps(env.engine, workflowName)
 	for _, depPath := range depPaths {
 		dep, err := env.LoadDependency(depPath)
 		if err != nil {
@@ -72,6 +87,9 @@ func (env *GFlowsEnv) GetLibPaths(workflowName string) ([]string, error) {
 		}
 		libPaths = append(libPaths, dep.LibsDir())
 	}
+	if env.engine != env.context.Config.Templates.Engine {
+		return env.context.ResolvePaths(libPaths), nil
+	}
 	contextLibPath := env.context.LibsDir()
 	libInfo, err := pkg.GetLibInfo(contextLibPath, env.fs)
 	if err != nil {
@@ -128,6 +128,32 @@ func TestGetLibPaths(t *testing.T) {
 	assert.Equal(t, []string{"/libs/some-lib", somePkg.LibsDir()}, paths)
 }
 
+func TestGetLibPathsForEngine(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: jsonnet",
+		"  defaults:",
+		"    libs: [/libs/some-lib]",
+		"  engines:",
+		"    ytt:",
+		"      libs: [/libs/ytt-lib]",
+		"  overrides:",
+		"    my-workflow:",
+		"      engine: ytt",
+		"      libs: [/libs/my-lib]",
+	}, "\n")
+	env, container := newTestEnv(config, fixtures.NewMockRoundTripper())
+	container.ContentWriter().SafelyWriteFile(".gflows/libs/lib.libsonnet", "{}")
+
+	paths, err := env.ForEngine("ytt").GetLibPaths("my-workflow")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/libs/ytt-lib", "/libs/my-lib"}, paths)
+
+	paths, err = env.GetLibPaths("other-workflow")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/libs/some-lib", ".gflows/libs"}, paths)
+}
+
 func TestGetLibPathsAddContextLibsDir(t *testing.T) {
 	config := strings.Join([]string{
 		"templates:",
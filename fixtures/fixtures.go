@@ -3,6 +3,7 @@ package fixtures
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 
@@ -120,9 +121,7 @@ std.manifestYamlDoc(workflow)
 `
 
 func ExampleWorkflow(sourceFileName string) string {
-	return fmt.Sprintf(`# File generated by gflows, do not modify
-# Source: .gflows/workflows/%s
-"jobs":
+	return GeneratedWorkflow(".gflows/workflows/"+sourceFileName, `"jobs":
   "test":
     "runs-on": "ubuntu-latest"
     "steps":
@@ -131,5 +130,13 @@ func ExampleWorkflow(sourceFileName string) string {
   "push":
     "branches":
     - "develop"
-`, sourceFileName)
+`)
+}
+
+// GeneratedWorkflow - returns the workflow with the header gflows adds to generated workflows
+func GeneratedWorkflow(source string, workflow string) string {
+	return fmt.Sprintf(`# File generated by gflows, do not modify
+# Source: %s
+# Checksum: %x
+%s`, source, sha256.Sum256([]byte(workflow)), workflow)
 }
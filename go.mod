@@ -3,6 +3,7 @@ module github.com/jbrunton/gflows
 go 1.14
 
 require (
+	cuelang.org/go v0.2.2
 	github.com/davecgh/go-spew v1.1.1
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/go-git/go-git/v5 v5.1.0
@@ -19,6 +20,8 @@ require (
 	github.com/thoas/go-funk v0.7.0
 	github.com/xeipuuv/gojsonschema v1.2.0
 	gopkg.in/yaml.v2 v2.2.4
+	gopkg.in/yaml.v3 v3.0.0-20200121175148-a6ecf24a6d71
+	mvdan.cc/sh/v3 v3.7.0
 )
 
 replace go.starlark.net => github.com/k14s/starlark-go v0.0.0-20200522161834-8a7b2030a110
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -62,13 +63,31 @@ func (writer *Writer) LogErrors(destination string, message string, errors []str
 	writer.logger.PrintStatusErrors(errors, true)
 }
 
-func (writer *Writer) UpdateFileContent(destination string, content string, details string) {
+// LogDiagnostics - prints an error message for the given destination file, together with the diagnostics for the
+// template (including source snippets)
+func (writer *Writer) LogDiagnostics(destination string, message string, diagnostics []string) {
+	writer.logger.Printfln("%11v %s %s", "error", destination, message)
+	writer.logger.PrintDiagnostics(diagnostics)
+}
+
+// ErrModified - returned by UpdateFileContent when the destination has been modified since it was generated
+var ErrModified = errors.New("file has been modified since it was generated")
+
+// UpdateFileContent - writes the content to the destination and logs what was done. If isModified is given and returns
+// true for the existing content (i.e. the file has been modified since it was generated), then the file is only
+// overwritten if force is true. Otherwise the error is logged and ErrModified returned.
+func (writer *Writer) UpdateFileContent(destination string, content string, details string, isModified func(existingContent string) bool, force bool) error {
 	var action string
 	exists, _ := writer.fs.Exists(destination)
 	if exists {
 		actualContent, _ := writer.fs.ReadFile(destination)
 		if string(actualContent) == content {
 			action = "identical"
+		} else if !force && isModified != nil && isModified(string(actualContent)) {
+			writer.LogErrors(destination, details, []string{
+				"Workflow has been modified since it was generated, run with --force to overwrite it",
+			})
+			return ErrModified
 		} else {
 			action = "update"
 		}
@@ -84,6 +103,7 @@ func (writer *Writer) UpdateFileContent(destination string, content string, deta
 	} else {
 		writer.logger.Printfln("%11v %s", action, destination)
 	}
+	return nil
 }
 
 func (writer *Writer) ApplyGenerator(sourceFs http.FileSystem, contextDir string, generator WorkflowGenerator) error {
@@ -103,7 +123,9 @@ func (writer *Writer) ApplyGenerator(sourceFs http.FileSystem, contextDir string
 		}
 
 		content := generator.renderTemplate(string(template))
-		writer.UpdateFileContent(destinationPath, content, "")
+		if err := writer.UpdateFileContent(destinationPath, content, "", nil, false); err != nil {
+			return err
+		}
 	}
 	return nil
 }
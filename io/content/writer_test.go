@@ -19,6 +19,16 @@ func TestLogErrors(t *testing.T) {
 	assert.Equal(t, expectedOutput, out.String())
 }
 
+func TestLogDiagnostics(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	writer := NewWriter(container.FileSystem(), container.Logger())
+
+	writer.LogDiagnostics("path/to/file", "error message", []string{"file:1: details\n  |\n1 | foo\n  | ^^^"})
+
+	expectedOutput := "      error path/to/file error message\n  ► file:1: details\n      |\n    1 | foo\n      | ^^^\n"
+	assert.Equal(t, expectedOutput, out.String())
+}
+
 func TestSafelyWriteFile(t *testing.T) {
 	container, _, _ := fixtures.NewTestContext("")
 	writer := NewWriter(container.FileSystem(), container.Logger())
@@ -33,7 +43,7 @@ func TestUpdateFileContentCreate(t *testing.T) {
 	container, _, out := fixtures.NewTestContext("")
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	writer.UpdateFileContent("path/to/file", "foobar", "(baz)", nil, false)
 
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
@@ -45,7 +55,7 @@ func TestUpdateFileContentUpdate(t *testing.T) {
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
 	writer.SafelyWriteFile("path/to/file", "foo")
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	writer.UpdateFileContent("path/to/file", "foobar", "(baz)", nil, false)
 
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
@@ -57,13 +67,34 @@ func TestUpdateFileContentIdentical(t *testing.T) {
 	writer := NewWriter(container.FileSystem(), container.Logger())
 
 	writer.SafelyWriteFile("path/to/file", "foobar")
-	writer.UpdateFileContent("path/to/file", "foobar", "(baz)")
+	writer.UpdateFileContent("path/to/file", "foobar", "(baz)", nil, false)
 
 	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
 	assert.Equal(t, "foobar", string(actualContent))
 	assert.Equal(t, "  identical path/to/file (baz)\n", out.String())
 }
 
+func TestUpdateFileContentModified(t *testing.T) {
+	container, _, out := fixtures.NewTestContext("")
+	writer := NewWriter(container.FileSystem(), container.Logger())
+	isModified := func(existingContent string) bool { return existingContent == "foo" }
+
+	writer.SafelyWriteFile("path/to/file", "foo")
+	err := writer.UpdateFileContent("path/to/file", "foobar", "(baz)", isModified, false)
+
+	assert.Equal(t, ErrModified, err)
+	actualContent, _ := container.FileSystem().ReadFile("path/to/file")
+	assert.Equal(t, "foo", string(actualContent))
+	assert.Contains(t, out.String(), "error path/to/file (baz)")
+	assert.Contains(t, out.String(), "Workflow has been modified since it was generated, run with --force to overwrite it")
+
+	err = writer.UpdateFileContent("path/to/file", "foobar", "(baz)", isModified, true)
+
+	assert.NoError(t, err)
+	actualContent, _ = container.FileSystem().ReadFile("path/to/file")
+	assert.Equal(t, "foobar", string(actualContent))
+}
+
 func TestApplyGenerator(t *testing.T) {
 	// arrange
 	sourceFs := fixtures.CreateTestFileSystem([]fixtures.File{
@@ -69,6 +69,18 @@ func (logger *Logger) PrintStatusErrors(errors []string, firstLineOnly bool) {
 	}
 }
 
+// PrintDiagnostics - prints formatted template diagnostics, indenting any source snippets and stack traces under each
+// message
+func (logger *Logger) PrintDiagnostics(diagnostics []string) {
+	for _, diagnostic := range diagnostics {
+		lines := strings.Split(diagnostic, "\n")
+		logger.Printfln("  ► %s", lines[0])
+		for _, line := range lines[1:] {
+			logger.Printfln("    %s", line)
+		}
+	}
+}
+
 // PrettyPrintDiff - prints a diff using syntax highlighting if bat is available
 func (logger *Logger) PrettyPrintDiff(patch string) {
 	logger.prettyPrint(patch, "diff")
@@ -0,0 +1,10 @@
+package workflows
+
+#Steps: {
+	checkout: uses: "actions/checkout@v4"
+
+	setup_go: {
+		uses: "actions/setup-go@v5"
+		with: "go-version": "^1.14.4"
+	}
+}
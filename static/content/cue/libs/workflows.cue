@@ -0,0 +1,8 @@
+package workflows
+
+#Git: main_branch: "develop"
+
+#Triggers: pull_request_defaults: {
+	pull_request: branches: [#Git.main_branch]
+	push: branches: [#Git.main_branch]
+}
@@ -0,0 +1,21 @@
+package workflows
+
+name: "gflows"
+on:   #Triggers.pull_request_defaults
+jobs: check_workflows: {
+	name:      "$JOB_NAME"
+	"runs-on": "ubuntu-latest"
+	steps: [
+		#Steps.checkout,
+		#Steps.setup_go,
+		{
+			uses: "jbrunton/setup-gflows@v1"
+			with: token: "${{ secrets.GITHUB_TOKEN }}"
+		},
+		{
+			name: "validate workflows"
+			run:  "gflows check"
+			env: GFLOWS_CONFIG: "$CONFIG_PATH"
+		},
+	]
+}
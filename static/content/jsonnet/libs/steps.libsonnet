@@ -13,9 +13,9 @@
     uses: action
   },
 
-  checkout: steps.uses('actions/checkout@v2'),
+  checkout: steps.uses('actions/checkout@v4'),
   
-  setup_go: steps.uses('actions/setup-go@v2') {
+  setup_go: steps.uses('actions/setup-go@v5') {
     with: {
       'go-version': '^1.14.4'
     }
@@ -8,7 +8,7 @@ import (
 
 
 func init() {
-	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00config-schema.jsonUT\x05\x00\x01\x80Cm8\xd4\x94\xdfN\xf30\x0c\xc5\xef\xfb\x14\x91\xbf\xef\x12i\xf7\xbb\x85\x07\xe0\x15\xd2\xc6\xed\xcc2\xa7J=\xa6	\xed\xdd\xd1\xc6:\xba\xfci\x81\x0e\x04\xb7\x8e}\xdc\x9c_O^\n\xa5\xc0`MLB\x8e;X\xaacI)\xd89\xbf\xae\xad\xdb\xdd;\xae\xa9\xb9\xd4\x95\x02\xd9\xb7\x08K\x05\xae|\xc2J\xe0\xae\xaf\xb7\xde\xb5\xe8\x85\xb0\x1bt+\x05\xd5\n\xab\xf5u-\xaf2\xa6t:\xeb\xaa\x15ntT\x1fW\x9cR=\x9d#\xeb\xd2\xa2I\x1e\x0e\xe4K\xe7,j\x86\xa8\xe7\x10nT\n\xb6\x9e\xa6\xf4:\xf1\xc4MB.\xa8D\xf2\xa0\x8d9Q\xd3\xf6qx\xb5Z\xdb\x0e\x8b\x91Q\xa8\x1c\x0b\xb2\xfcJ\x13ox\xeb\"#\xf31\x89~\xfc2:=vn\x05\xc1Mk\xb5\xe0\xfc\xecX*\xb3\xc9\xd1\xde\xeb\xfdupHp\x93\xc8L\xfeW{\xf7h\xe0\x10\x18l\x91\x0dr\x15c\xfe\xae\xed_0\xbb8\xb7\xa7LM\x19\n\x0d\xc9j[>\x90O\x11\x19~]\xcf\xb1\x7f\x03\xbb9\x08\x91\x1bb\xcc\xd9\x18\x9ar\x8d\xa1\xd6[+\x11\x82\xff\x1e\xeb\xe3\xec\xbf\xc5\xe0\xe5^\x04\x0fvR\xd1=\xa3\xf7db\xaa\x19\xb7\x03\x92\x9f[<\x97o\x10\xa6?\x01!H\xfe\xcfA\xc8-\xbe\x11\x84\x89\xc6\xb7\x1c\x8e6\x1d\x8a\xd7\x00\x00\x00\xff\xffPK\x07\x08\n\x07\xe1\xaeN\x01\x00\x00l\x08\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\x98\x82\x8d\x12G\xbc~E\xd9\xcep\xfe\xaf\x82\x8bJ\xe0\x08\x81g\x82\xa0	\xda\xeb\xac[\xae]\x05\x0f\"\x18\xcd\xd6\xec\x11#\xdbX\x86\xfa\xa3\x0bNC*b*\x18\xc3\xfe\xc4\x8d\xbf\x8c\x87RRo\xac\xf2wt\xddg\xae\xdd\x916\x9c<\x9c\xda\xeey{\x9d\xdfMwwF\xcb:\xf7F\xd9;\x00\x92\xc8B\x1e\xa6\xac\"d\xee\x17\x00\x00\xff\xffPK\x07\x08\xd9\x11\x87n\x81\x00\x00\x00\x97\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8\xaa\xe6RP\xc8M\xcc\xcc\x8bO*J\xccK\xce\xb0RPOI-K\xcd\xc9/P\xe7\xaa\xe5\x02\x04\x00\x00\xff\xffPK\x07\x082r\xa7\xff#\x00\x00\x00\x1d\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1c\x00	\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8l\x8e\xcdN\x850\x10\x85\xf7\xf3\x14gWn\xc2\xc5\\\xc3\xaa\x89\x89O\xa2iJ\x05\"t\x08\xd3\xe2\xc2\xf0\xee\xa6\xa5(\x0b7\xb38?\xdf\x9co\x02&\xb6f\x82\x04\xb7\x08^ n\xfa\xa8\x89\x805\xfa\xca\xf2<\x1b\xdf\xdd\xb4FJfQ\xa3\xa8\x04\xec5\x01\x04x3\xbb\xaeJ\xb7\xc6\xa5\x93\x99\xcd\x15T0)\xa9\xf3= \x04DqR\x19\x1bF\xf6\x7f\xef\x92\xa8q\xa8\xbfI;8\xfb\xc91\x9c\xfc\xdcTGH\x9eN\xf7u{V\xb72O\\\x88\xcb{\xcf\xff7\xb2{\xef97\xca\xe3\xaf1\x0c\xe7\x08@\xf5|\xdf\xdc*#{\xa5\xa1\xde\x1e\xcd\xa3mZ\x95\xdd=\xed\xa2\x9d~\x02\x00\x00\xff\xffPK\x07\x08\xf4\xa3Z)\xb6\x00\x00\x00J\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8\x9c\x8d1\xaa\xc30\x10D\xfb=\xc5tn\x8c\x0f\xe0\xcf?I\x08Bv\xd6\xf2\xc2Zr\xa4u\x15t\xf7\x10\x12d\xd2\xa6\x9cy\xc3\x1bM\xb3W\x0417\xa7\xb8H\xc0?d\xdbS6tAlP\x99J\x8a\x91\xad\xfb#z\x10`YB\xe0\\F\xbc\x12\xb0\x1f\xaa.\xf3\xfd\xe0b\xee\xc6\x8b?\xd4\x1a\xfc\xc6g\x0bL\xd9\xc7y\xe52\xe2r~\x0f\x9b\x97\xe8\xde\xe8\xfa\x99\xd6\xbe\x99\xca\xfa\x9b\x81\x9a\xa7\xf6T\xe9\x19\x00\x00\xff\xffPK\x07\x08\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8l\x91\xdfk\xdb0\x10\xc7\xdf\xfdW\x1c\xa5\xa0\x14\x1c\x8f\xbdz\x14\xd6nm\xda\xfdH\x06\xcb\x18c\x0c!;\x17W\x89\"e\xbaS\xf2\x10\xfc\xbf\x0f)\xb1\xe3\x95\xf8\xc5\xbe\xd3\xc7|?\xba3\xaeV\x06\x1a\xcd\xb2vv\xa9\x1b\xb8\x05\xbd\xd9:\xcf \x1a\xcd\x85\xd1\x159k\x91\xc5\xbb\xec\x88\x12\xe3\x96\x06T\xaa/p{\xe7\xd7K\xe3\xf6C\xb6\xef\xfd\xc7\x9f~\xa8_\xb0^\xcb\x1e\x91+W\xc1-\x1c2\x00a\xd5\x06E	\xe2\xfa\xd3\xec^N\xef\xbe>\x88<\xb6}\xb04v6\x9e\x84*X\x0ec\xa3\x18\x89\xd3i\xf2*\xe1w\x06p*\x8a\x94\xe0\x02\xe7\x83\x1e!\x87\xadl\xdc\xb0\x17\x08i$V\x95\x0f\x96\x9d}\x93\x90q\x93\xac\xde\xef\xde\x8a\x9b$\x15\x9f\xbd\xe6\x97\xb2\xaf\x00\xd8\xad\xd1\x96pu}8\x00a\xed\x91\xa9\x98<\xcf\x9f~\xdc\xcb\xf9\xec\xf3\xc3\x14\xda\xf6*?\xd1mz\xb7\xc3\xdcx\xcd\xc5H\xec\x94\xd1\x0b\xc5x\x1e\xa1\xc8A\x1c\xf3\x8fS\x1a(\xa0\xdd\x0d\x0d&\x8f_f?\xbf\xcb\x0f\xb3\xe9\xe3\xf3$N\xec\xf8%\xbf\xdd\xcd\x9fD\x97\x9c\x9f\xa3\xffdm\xbf\x80.\xee4\xf5hSv\xb9i\xa4\xce\x96g\xa7\x82\xbdn\x1a\xf4Tl\x831\xd2\xe3\xdf\x80\xc4r\x81K\x15\x0cS\xe4W\xae\xa2N\xee\xd5v\xcbK\xeb\xce\x92T\x14\"^\x14\x1be\xf5\x12\x89\x7f\xa9\x8d\xf9\xe8\xeaQ\x87\xded\xff\x02\x00\x00\xff\xffPK\x07\x08Piq\xc9o\x01\x00\x00\xb4\x02\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x00	\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\xb8\x05\x1b%\x8eP\xdf\xbe\xa2lg8\xffW\xc1I%p\x84\xc0\x13A\xd0\x04\xedy\xd25\xd7\xae\x82\x1b\x11\x8cfK\xf6\x88\x91m,C\xfd\xd2\x19\xdfC*b*\x18\xc3\xf6\xc4\x95?\x8c\xbbRRo\xac\xf2wt\xd9f\xae\xdd\x9e6\x9c<\x1c\xda\xee~y\x1c\x9fMwuF\xf32\xf5F\xd9;\x00\x92\xc8B\x1e\xbef\xee\x17\x00\x00\xff\xffPK\x07\x08S\xddb\xa7\x80\x00\x00\x00\x93\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8RvPHIMS(N-)-\x88O\xcf\xd7\xd0\xb4\xe2RP(-N-\xb6RHL.\xc9\xcc\xcf+\xd6\x07\xcb\xe9\xa6\xe7;\x94\x19q)(\x94g\x96d\x80\xd4((\xa4\xe7\xeb\x96\xa5\x16\x15g\xe6\xe7Y)(\xc5\x19\xea\x19\x9a\xe8\x99(q);(\xa4\xe6\xa5p\x01\x02\x00\x00\xff\xffPK\x07\x08\x10\xb0+\xfeY\x00\x00\x00X\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x13\x00	\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8RvHI,I\xd4/K\xcc)M-\xe6\xd2\xd5\xd5\xe5J\xcf,\xb1\xe2RP\xc8M\xcc\xcc\x8bO*J\xccK\xce\xb0RHI-K\xcd\xc9/\xe0\x02\x04\x00\x00\xff\xffPK\x07\x08\xe0M\xb2'4\x00\x00\x00.\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8\x9c\x8c\xb1\x0e\xc20\x0cD\xf7|\x85\xd5,\xad\x04\xf9\x80N\xf9\x93\xe8\xc0.\xadd\x02\xd4\x0e\x12\x7f\x8f\xdaN\xacL\xa7\xa7\xbb{1\x93>\xc0}\x97?\xee#\xc3\xd1\x9d\xa8\xdbs\x08!fb\x99\xe8\xd9T\xcb*\xaf&\xe6\x85eBS\xb7~\x18\x03\xfdT\x1b\x13]V\xd4\xeb,v\xd0\x996\x07\x1c\xe9\x0dmb\xe9\xb6x\xbac\xa9\xe5\xd8\xed\n\x9b\xff\xba\xc6LR9|\x03\x00\x00\xff\xffPK\x07\x08\xbd\xcf\x85\xf3u\x00\x00\x00\xc3\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1f\x00	\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8l\x90\xcdn\xc20\x10\x84\xef~\x8a\x95\xe1@\xa4\x06\xd4\x1e}\nT\xfc\xf5\x87T*U\x8f\x96\x13\x16\x08\x18\x9b\xc6\xeb\xa0\n\xf1\xeeU\x9d\x90\n\xa9\xb7\xd5\xce\xce\xa7\x99\xed$\xa0\xadZ\xf5\xf8\xc9\x96\xfb\xb5\xb6'\xd7\xd7E\xd6\xff>h~\x07\xfc\xe8\xb5\x96%~yt$W\xb8V^\x93\xe3\x11k]\x8e\xf0x\xe3pH\xfe(7\x96G\x8c\x19u@\x01\x9b@e\x8c[\xc3\x05t\x12\xf8\x17\xda\x8b\x18\xdb\xd9\xcc	\x06\x90o1\xdf\xcb6\xd0\xef\n\xa0\x86u\x9f\xd2\x91\\\x0c_\xc7aWz\xe3bk\x04\xf8\xcc\x1b\xf2\xb1V\x84\x8e\x82\x14\x82\xd5\xce\x18\xbcC'@\xe5TX\xe3\x06\x01o=%\xd5C\xa3w\x12\xb8\xe6\xeeE7\x9e]VzC\xd6\x0c\x82\x1e\xd7]\x92\xea>\x1c\x01\x9c\n\xda\x8af\x06 \xbbG#\xa0{>\x83\xc3\xbcDr\xfd\xe9|9\xfb\x18\xc9e\xfa<^\xc0\xe5\xd2\xb0\xeb.\x95\xd2\xc5J\x11B\xdb\xb4!\xa1\xa9\xfe\xa0\xd3\xc9K\xfa\xf9.\x1f\xd3\xc5d>\x15\xd0\xad\x07\xf96\\\xce\x9a\x9b\xd2\x9b\xeb\x97\xeb\xd7\xb1\x9f\x00\x00\x00\xff\xffPK\x07\x08\xf1\xc5S\xfb\x1f\x01\x00\x00\xd5\x01\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\n\x07\xe1\xaeN\x01\x00\x00l\x08\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x00\x00\x00\x00config-schema.jsonUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd9\x11\x87n\x81\x00\x00\x00\x97\x00\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x97\x01\x00\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(2r\xa7\xff#\x00\x00\x00\x1d\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81a\x02\x00\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xf4\xa3Z)\xb6\x00\x00\x00J\x01\x00\x00\x1c\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xd5\x02\x00\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xde\x03\x00\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(Piq\xc9o\x01\x00\x00\xb4\x02\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xb6\x04\x00\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(S\xddb\xa7\x80\x00\x00\x00\x93\x00\x00\x00\x0e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81|\x06\x00\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\x10\xb0+\xfeY\x00\x00\x00X\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81A\x07\x00\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe0M\xb2'4\x00\x00\x00.\x00\x00\x00\x13\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xe7\x07\x00\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbd\xcf\x85\xf3u\x00\x00\x00\xc3\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81e\x08\x00\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xf1\xc5S\xfb\x1f\x01\x00\x00\xd5\x01\x00\x00\x1f\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81+	\x00\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8PK\x05\x06\x00\x00\x00\x00\x0b\x00\x0b\x00g\x03\x00\x00\xa0\n\x00\x00\x00\x00"
+	data := "PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00config-schema.jsonUT\x05\x00\x01\x80Cm8\xe4X=o\xdb0\x10\xdd\xf5+\x88k\xc6\x04\xe9P\x14h\xb6\xa2E\xe7N]\x82\x148\x8b'\x99\x0dE\xaa$\x95\x0f\x04\xfe\xef\x05m7\xa5(\xd2RPGvkM\x06!\xbe\xbb{\xef\xf1|\xe2S\xc1\x18p\xaa\x84\x12Nhe\xe1\x8a\xf9%\xc6\xe0^\x9b\xdbJ\xea\xfbOZU\xa2~^g\x0c\xdccKp\xc5@/~P\xe9\xe0\xfc\xf7zktK\xc6	\xfa\x83\xe2\x1f(\x97T\xde\xf6\xd7\xf2(\xbb\x90\xfc\x03\xb6\\R\x83\x11\xda\x18\xe2\x18\xaa\x7f\x80\x14.$\xf1\x04t\x0f~\xa1\xb5$T\x10\x14\xb3yVqD\xc6\xa03b\x0c\xcf:#T\x9d\x80+F\xe0\x019_\xab\x86\xf2kH}\x85\xd2R\xb1c+\x94Z9R.\x91\xd9Q\x92\xd8hN\x89\\\xb7\xa2u\x0d\\\xb1k\xa0\x07\xf46b`\xa9A\xe5D	7sR\xda\x92i\x84\xb5\xbd3\x94\xa8\xfb\x98\xbc\xd9\xe0C\x8e\xd5\x91twQ\x95\xc2\x0bu2\x84\x1c\xce\x19\xdc\x1b\xe1\xc8\xffPZQB\xab\xa1Z\xc3\x95=\x1e\x89\x06\x9d\x11)>\xc6\xb8x}\xe9\xa2\x95=\x16\xed{)\xef$\x9dX\xd9B\xb5\x9d\xb3'\xa6u\x89R\x9eZ\xcdX\xf6\x87\x9a\xd3\xa8\xda.I\xca\x13\xab\x99Sk\xa8\xc4\xe3\x95;&\xdbw_gD\x99\x1a\xc2\x0e\xce\xa6u\xd4\xfe\xf7\xbd\xa2\xc8\xd4?\x0dbUD\xd4\x8do\xdb\xb2\x0c\xad\xecj\xa1\xf6\xf0]\xa5\x9b\x06U\xec\xc9\xecwE 2\xa0\xa9mn\x1f\x1a\x83\x8f=iA8j\x86\x8af#\x85'=\x8cJ\x0f\x8eTjD\x9e!\xf6\x1d\x1a\xe1\xbd\x17C\xe5X\x9f>\xe2NK\xa5\x88\xe8\x00C?;a\xd6g\xe1\xfaY\xca\x9b\x97\xbb\xc9Q\xd3Jt\xf4\xf7~\"U\x0b\x15\xcf\x82\xd9\xeaBa\xa5X\x1c\xc0N\x9cZR\x9cT9\xfc\xc3\x9a\xc1\xcc\x1c\x1d~C\xd9\x1d\"\xb6\xbe##\xf1q\xae\xc8E\x94\xc1\x04s\x16[\xa9R&L\x19\x10j\xe1\x96\xdd\xe2\xb30AMIMV\xe7\xfd\xfb)\x9b\xda\xf0\xfa\x96\xe7Ta'\x07_\x11pf\xa8\xf2{\xdf\\\x06\xb7j\x97\xd1eZ\x12\xd1\x8bj\x04\x1f\xfa)\xc3v\xf8\xce\x8b\x03\xf7\x9cUD\xe5M\xd07j>\xff\x84\x08Q\xa7\x9cO\x84\\\xe0\x9e\x08Ac\xdb4b\x9bc$2\xf7\xec\xe9m\xe6\x97\xd9\xd2\xeb\x8dK\xa3\xdcm.\x9b\xbbA\xaf\xdb\x03{\xd1?DX\xa0oj\xe8\x1c\x19\xe5\xf3\xff~\xfd\xf6\xe2\x03^T\x1f/\xbe\xdc<\xbd\x7f\xb7:\xcb\xa4]D\xdcN?w#/\xae\x11w\xbf\xb4*~\x0d\x00PK\x07\x08OG\x9f\x0f\x89\x02\x00\x00\xfb\x17\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x00	\x00cue/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n\x90\x13\xdc\x82\x8d\x12G\xbc~E\xd9\xcep\xfe\xaf\x82\x8bJ\xe0\x08\x81g\x82\xa0	\xda\xeb\xac[\xae]\x05\x0f\"\x98\xcc\xd6\xec\x11#\xdbT\x86z\xd4\x05?C*b*\x18\xc3\xfe\xc4\x8d\xbf\x8c\x87RRo\xac\xf2wt\xddg\xae\xdd\x916\x9c<\x9c\xda\xeey{\x9d\xdfMwwF\xcb:\xf7F\xd9;\x00\x92\xc8B\x1e\xc6B\xee7\x00PK\x07\x08\x8f#\xdd\xca|\x00\x00\x00\x93\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00cue/libs/steps.cueUT\x05\x00\x01\x80Cm8\\\xcb=\n\xc30\x0c@\xe1Y:\x85P\xe7\xa4\x04\xd2\xc5S\xee\xd0\xbd%\x18\xd5	.\x91\x89\xfc3\x94\xdc\xbd\x10\xe8\xd2\xf9{/\xcd>\xceA\xa8\xe9\x1e_om\x86x\xb9gI\xe6\xe8\x83\xe0\x17\xf1QKvTL\xcc\x11\xcf>\xaf\xba\xd9\xf5\x07S\x1d\x19\x11LrI\xcf\xa0\xe7\x04\x7f\xed\x89]\xd0\xa9\xde\x18\x01\xda\x9a\x17G\x1c\xb4\xab\xb2\xdb\xaa\x1b;\xe2\xc7\xd0\x0fc?2\xc2\x81\x07~\x07\x00PK\x07\x08\xbf\xfa\n\xafx\x00\x00\x00\x95\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00cue/libs/workflows.cueUT\x05\x00\x01\x80Cm8\x84\xcc1\xae\xc20\x0c\x80\xe1\xf9\xf9\x14V\xbb\xbf\x03\xf8\x02\\\x80\x0d\xa1\xc8\xb4n\x1a\xd54\xc1N\xe8\x80\xb8;\xaaX\xba1\xff\xbf\xbe\xc2\xc3\xc2Qp\xcb\xb6L\x9a7\x07\xe8O\xa9\x12\xde9\xad\xe1f\xbc\x0e3a7\xcaS4\x97\x0e\xa0?[\x8aQ\xcc	KS\x0d&\x8f&^\xc3(\x137\xadN\xf8\x82\xbfc!\xfc*\xe2\x84\x97\xdd\xfe?\xd0\xd7\xfd\xf5\xf9\xc7\xf3\x86\xcf\x00PK\x07\x08~c)*r\x00\x00\x00\xa7\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x18\x00	\x00cue/workflows/gflows.cueUT\x05\x00\x01\x80Cm8L\x90\xcbn\xc20\x10E\xd7\xf6W\x8c\x0c\xcbB\xd5\xadW\x85\x8aW\x1f\xa4\x12\xa9\xba\xa8*+\x84!@R;\xf5\x8ca\x11\xe5\xdf+\x07J\xeb\xd5\xf8\xea\xea\x8c}\xea,/\xb3\x02\xe1\xe4|\xb9\xad\xdc\x89\xa4\xb4\xd9\x17jPEwU\xd2Y\x0d\x00\xbd\xd4\xef\x8b\x02=\x0d\xebPU\xc6\xe3w@b\xb3\xc1m\x16*&ypk\xd2\x90\xef0/\xcd\x95\xa5\xa1\x91\xe2\x8c\xeb\x8e\xea?&c\xb3\x1c\xbdL\x94\x14\xca\x07K\x03g\x95\x06\x15\xd6\xc1r\x18T\x19#\xb1\x92\x82\x18k\xd2\xf0!\x85\xe8\xad\xe2<\xec\xc8.\xf0\xcd_D\xc8\xa16\x85\x8bQ#\x85\x10\x81\x904\xa8\xc3\xda\x07\xcb\xce\xdev\x85\xc1\xf9\x1f\xf7\xc7;\x15;\xa7=\xef4\xb0+\xd1jP\xfd\xa6\x01\xc2\xdc#\xd3p\xb6H\xe7oc\x93&O\x93%\xb4ml\xb7W\xf4\xc5\xc91\xab\xf6\x9b\x8c\xff\xe9\x8a5\xe1Ctt1\x06\xddS\xbb\x1c\xedQ\xc3l\xfa\x9c\xbc\xaf\xccC\xb2\x9c.fq\xe7y2\xaf\xa3t\xfe\xbb\xe4S\xb6\xf2g\x00PK\x07\x08z\xb2\x83_\x0e\x01\x00\x00\x8a\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x10\x00	\x00deprecations.ymlUT\x05\x00\x01\x80Cm8\xac\x93\xcfn\xda@\x10\x87\xef~\x8a\x91\xd2\xa37\xc5$\xd0\x96\x13T\x8d\x9a^\xd2H5\xbdTU5x'x\x13\xb3c\xed\xce\xae\x95\xb7\xaf\xf8\xd7\x02!\xe0Bo\x08\xef|\xdf\xce\xec\xfc.\xe0\x13\xd5\x8e\n\x14\xd2\xd0\xb0{z\xa8\xb8\x81\x82g3\xb4\xda\xa7\xe0\x82\xb5\xe4\xa0\xc2	U\x1e\xd0j\xc0B\x0c[\x88\xe4\xbca\xeb\xc1Q\xcdNH\xc3\xe4\x19\xa4$\xd0+\xde\xe2cQR\xf1t\x99$k\xe0 \x01P`qF\x03\xf0$\x8a\x83\xd4A\x12\x00\x00\xac\x84\x9cE1\x91\x06\xd08#\x04\xc2\xf0\xe6\xf3\x97\xfcv\xfc\xf1\xd7\xd7q~?\xce\xc1X/\x84z\x93\x82\x91\x94\x17\x14jA\xf9\x96\x8f\xf2\x9b}\x10\x12E6\xb6 \xdc\xdc}\xdfS\x8fZ\xab\x1a\xa5l\x01\xb8\x1f\xe5\xb7\x7f\x08\xc9r\xbc\xab\xb1,\x86<\x800	V\x82\xca\xfa\x97\x9d\xeb\x97\xc0\xe0i}\xa2B!/[\xd7\xd9A\xbc?\x1b\xd1\xed\x9c\x8e\x98a\xc1^e\x9d\xcb\xac\xb7\xbf\x8f\xe5\x81\xa3\x80\xec\xac\xea\xeeY\xd5W\xa7U7\xc6jn\xbc\xeav\xb2\xfe~\xc2\xfa\xc4\xeb\xddo0>\xfc\x13#\xb9\x80\xd1\"\xa4\x1e\x9a\x92=Ae\xfc<\xde3|d\xb7\x11\xdc`\x81-\xdc\xb1&\xc8\xba\xc0n\xf5\xb3\x9f,#\xbe\x15\xd6\xd5_o\x17\x81\xe6Ud\xd7\xa8\x01\xfc\x88Y\n\xb1\x9bB\xbc\xfa\xb9\xff\xae\xbb\x80a\xbc\xde\xda\x99m\x8d'	\xb5\xb2\xac\xe9d\xd1_D\x1bU\xfd,%\xdb\xd7e)\xc4\xebV\xc2%h\x18{G\xbb\x9b\xf2\x99\xbdM\xb9\x8d\xe6\x11#\x9e)\x9a#\x0e\x0f\xb1\xc0\xa2<\xfd\xa9\x16\xd5\x87\x05\xa1\xae\x18\xb5B'\xe6\x01\x8b\xd3\xd7o\x87sX\xaa\xb9\xb1\xffG\xfb\x82tX<5R\x86\x89\xf2\x853\xf5\x81^\xe7K\x99B\xec\xa5\x10\xfbG\x1er\x8b8\x8c\xef\xc0X/\x84:\xf9=\x00PK\x07\x08\xa0\xb5\xe8\xa7\xd8\x01\x00\x00\x01\x08\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x15\x00	\x00gotemplate/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbd\xae\x830\x0c\xc5\xf1=Oa\x89;\xe3=\xe3-*e\xed\xc7\\A\xe5\x04\xb7\xc1\x8e\x12#^\xbf\xa2lg8\xff_\x03'\x95\xc0\x11\x02'\x82\xa0\x05\xfas\xd2\xad\xb6\xae\x81\x1b\x11\xccf\xb9z\xc4\xc86\xafS\xfb\xd2\x05\xdfSY\xc5T0\x86\xfd\x89\x1b\x7f\x18\x0fe-\xa3\xb1\xca\xcf\xd1\xbc\xcf\xda\xba#\xed\xb8x\xf8\xeb\x87\xfb\xe5\xf1\xff\xec\x86\xab3Zr\x1a\x8d\xaaw\x00$\x91\x85<D5Zr\x1a\x8d\xdcw\x00PK\x07\x08O\x9d\x06\xad\x81\x00\x00\x00\x9a\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00gotemplate/libs/steps.tmplUT\x05\x00\x01\x80Cm8\x00b\x00\x9d\xff{{ define \"setup_go\" -}}\n- uses: actions/setup-go@v5\n  with:\n    go-version: \"^1.14.4\"\n{{- end }}\n\x03\x00PK\x07\x08\x00\x0f\xb6\x0ei\x00\x00\x00b\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00gotemplate/libs/values.ymlUT\x05\x00\x01\x80Cm8\x00\x15\x00\xea\xffmain_branch: develop\n\x03\x00PK\x07\x08\xddW3F\x1c\x00\x00\x00\x15\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1e\x00	\x00gotemplate/libs/workflows.tmplUT\x05\x00\x01\x80Cm8\x94\xcb1\x0e\xc2@\x0cD\xd1~O1J\xbf9\x00\x07\xa1]-\xecD\x89d,\x88\xe3\xca\xf2\xdd\x11\xd0\xd0\xa6\x1b\xe9\xcd\x8f\xc0\xe0\xb2)1=]\xa4\xed|9\xedh\x83Kw9lB\xcd,\xfft)\xc0m\xefz_i\x9f]\x11\x81\xf9\xda\xc5i\xf3\xa3o\xda~\x8aog\xeb\x99\x7fD\x05u \xb3\xbc\x07\x00PK\x07\x081p\x9a\x0b_\x00\x00\x00\x99\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00$\x00	\x00gotemplate/workflows/gflows.yml.tmplUT\x05\x00\x01\x80Cm8T\x91Mo\xf20\x0c\xc7\xef\xfd\x14\x16\xe2Z\xd0\xf3\x88SN\xc0\xc4\xdb^\xe8\xa41\xed\x18\x95\xd4@i\xe6\xb0\xc6.\x87\xae\xdf}\"\xed\x86z\xb3\x9c\x9f\x7f\xf1?\xa1\xf4\x13\x15\x1c\x0f\xd6]}\x149R\x11@]\xc7\x90\x93\xb1\x92!\x0c.b\xad.\xf1K\xd0\xb3\xce\xf0\x90\x8ae?\x80\x11|\x03\xe5\x94!1\xfc\x87\xa6\x89\xa2\xb3\xdb\xfb\xdb\xb49\xa1)\xf4\xd5\x95E\x90\xdeZ\x00\xed5\xc3\xc7d\xae\xb7\xb3\x97E\xe8\x95B>v\xa4@\xf6B,\xb1M\x19=\x87#\xcfx\xe9&c\x10\x8f^Aj8w\xe4\xc7A\xef\x84\xa7\xd5$\xa0\xbde=\xb2\\\xf4\xd1\xf5\xf7\x9b@\xd3\xf4\\\xe7})\xc4\x8e\xc6\x81\x8f\xdb\xf4\xd3\xea_\x80\x00\xae9\x9fTW\x03\xb0+\x90\x14\x0c\xeb\x1a<\x9a\x12\xd9\x8fV\x9b\xdd\xfa}\xaew\xc9\xd3b{w\xb7\x19\xab\xd4\xe6Y\xca\x08\x7f/\xd0\x99\x90\xaa\xbbt\xb5|N>\xde\xf4C\xb2]nV\n\x86m\xa1_g\xbbu\xc7\x94B\xbf\xff\x02\xe6\x84\xa6\x88~\x06\x00PK\x07\x08$\xf2\x05\x07\x0d\x01\x00\x00\xab\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x12\x00	\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\x98\x82\x8d\x12G\xbc~E\xd9\xcep\xfe\xaf\x82\x8bJ\xe0\x08\x81g\x82\xa0	\xda\xeb\xac[\xae]\x05\x0f\"\x18\xcd\xd6\xec\x11#\xdbX\x86\xfa\xa3\x0bNC*b*\x18\xc3\xfe\xc4\x8d\xbf\x8c\x87RRo\xac\xf2wt\xddg\xae\xdd\x916\x9c<\x9c\xda\xeey{\x9d\xdfMwwF\xcb:\xf7F\xd9;\x00\x92\xc8B\x1e\xa6\xac\"d\xee7\x00PK\x07\x08\xd9\x11\x87n~\x00\x00\x00\x97\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8\x00\x1d\x00\xe2\xff{\n  main_branch: 'develop'\n}\n\x03\x00PK\x07\x082r\xa7\xff$\x00\x00\x00\x1d\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1c\x00	\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8l\x8e\xc1j\xc4 \x14E\xf7\xef+\xeeN\x072)\x03\xe9F(\xcc\x97\xb4\x88c3\xa1\x89o\xc8\xd3tQ\xfc\xf7\xa2\x89m\x17\xdd\xb8\xb8\xf7\xbc\xe3\xfd\"`fggH\xf4\x0f\xc1\x0b\xc4\xcf\xef\x1d\x11\xb0\xa6\xa0\x1d/\x8b\x0d\xb7\x931(d\x0d\x0d\x8e\x94\x80\xdc\x11@@\xb0\x8b\xbf\xe9\xf2v\xad-7\xd5\xd9\xff\x15\x1d\x9aB\x9a\xfa\xee\x12\x02\x92x\xd1\xd6\xc5\x89\xc3\xefw%4\xd8\xd3\x1f\xd2\xdd\xbd\xfb\xe0\x14\x9b\xbf@Z\xed\x90<\xb5\xf6\xba\x0d\xeat\xcc\x13\x1f\xd3\xe3m\xe4\xff/j{\x1e\xf9\xba=\xab6\xf0s\x8a\xf76\x02P#\x9f7\xbf\xca\xc4A\x19\xa8\xd7K\x7f\x19\xfaA\xd56\x13\x90)\xd3\xf7\x00PK\x07\x08\xa4\xcd\x015\xb7\x00\x00\x00J\x01\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8\x9c\x8d=\x0e\xc20\x0c\x85\xf7\x9c\xe2m]\xaa\x1e\xa0\x88\x93 \x14\xa5\xc5M-\xb9IH\x9c	\xe5\xee\x08\xa8\xa8X\xd9\xec\xf7\xbd\x1f\x89\xb3\x13xV;\xc7\xb0\xb0\xc7\x19\xbc\xa5\x98\x15\x9dg\x1d\x84\xa7\x12C \xedN\xc6<\x0c\xa0\x99\xbd\xa7\\F\xbc> U\x11\x9b\xe9^\xa9\xa8\xbd\xd1\xe2\xaa\xe8\x17\xfe\xe2C\x05\xa6\xec\xc2\xbcR\x19q9\xb6\x87\xcdq\xb0\x1ft\xdd\xad\xad\xdf\x8fT\xcb\xfa_\xc3;\xd2z\x03\xb4\xde4\xf3\x1c\x00PK\x07\x08\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00	\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8l\x91Mk\xdb@\x10\x86\xef\xfa\x15C\x08\xac\x03\xb2J\xaf*\x81&m\xe2\xa4\x1fv\xa1.\xa5\x94\xb2H\xf2HY{\xbd\xe3\xee\xccZ\x07\xa3\xff^\xb4\xfeR\x83u\x91f\xf6A\xef\xb33\x96\xaa\xc2BcDW\xe4j\xd3\xc0-\x98\xf5\x86\xbc\x80j\x8cd\xd6\x94L\xce\xa1\xa8w\xc9\x1ee\xc1\x0d\x0f\xa8X_\xe0Z\xf2\xab\xdaR;dO\xbd\xff\xf8\xc3\x8f\xab\x17\xacV\xfa\x84\xe8%\x95p\x0b\xbb\x04@\xb9b\x8d*\x07u\xfdiv\xaf\xa7w_\x1fT\xda\xb7}p<&\xd7\x9f\x8428	c[\x08\xb2\xc4\xd3\xe8\x95\xc3\xef\x04\xe0Pd1\x81\x82\xa4\x83\x1e\xa3\x84\x8dnh\xd8\x0b\x8c<R\xcb\xd2\x07'\xe4\xdeDd\xdc\xc4\xcb\xbc\xdf\xbeU7Q\xaa\x7fZ#/\xf9\xa9\x02\x10Z\xa1\xcb\xe1\xeaz\xb7\x03\xc6\xca\xa3p6y\x9e?\xfd\xb8\xd7\xf3\xd9\xe7\x87)t\xddUz\xa0\xbb\xf8\xee\x86\xb9\xfd5\x17#\xb5-\xacY\x14\x82\xe7\x11\xaa\x14\xd4>\x7f?\xa5\x81\x02\xba\xed\xd0`\xf2\xf8e\xf6\xf3\xbb\xfe0\x9b>>O\xfa\x89\xed\xbf\xf4\xb7\xbb\xf9\x93:&\xa7\xe7\xe8?IwZ\xc01\xee0\xf5\xde&?\xe6\xc6\x91\x92\xcb\xcfN\x99x\xd34\xe89\xdb\x04k\xb5\xc7\xbf\x01Y\xf4\x02\xeb\"X\xe1\x9e_R\xc9G\xb9W\xdb\xcd/\xad;\x89R\xbd\x10\xcb\"[\x17\xce\xd4\xc8\xf2\xabX\xdb\x8fT\x8dZ\xf2\xab\xdaR{\x93\xfc\x1b\x00PK\x07\x08Piq\xc9p\x01\x00\x00\xb4\x02\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0f\x00	\x00yaml/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\xb8\x0d6J\x8cP\xdf\xbe\xa2lg8\xffW\xc1I%p\x84\xc0\x89 h\x86\xf6\x9ct-\xb5\xab\xe0F\x04\xa3\xd9\\<bd\x1b\x97\xa1~\xe9\x84\xef!/b*\x18\xc3\xf6\xc4\x95?\x8c\xbb\xb2\xe4\xdeX\xe5\xef\xe8\xbc\xcdR\xbb=m8{8\xb4\xdd\xfd\xf28>\x9b\xee\xea\x8c\xa69\xf5F\xc5;\x00\x92\xc8B\x1e\xbe\xfd\x94\xdco\x00PK\x07\x08s\x97\x85\x87~\x00\x00\x00\x94\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00yaml/libs/setup-go.ymlUT\x05\x00\x01\x80Cm8\x008\x00\xc7\xffuses: actions/setup-go@v5\nwith:\n  go-version: \"^1.14.4\"\n\x03\x00PK\x07\x08iV\xacG?\x00\x00\x008\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x19\x00	\x00yaml/workflows/gflows.ymlUT\x05\x00\x01\x80Cm8T\x91Oo\xdb0\x0c\xc5\xef\xfa\x14o]\xb0\xc30\xa7\x18\xb0\x93Om\x876\xed\xfe$\x03\x96aGC\x96\x99\xd8\xb5,z\"\xe5\xa6(\xfa\xdd\x07;N\xd7\xdd\xa8\xc7\xf7~ \xa9\xb7\xd8r\x0fO\x03y\xb4\xf4(\xe8#\xed\x9a\x03Uxh\xb4\xc6\xd9!;\x83\x8d\x84H\x1d\x0fTa\x17\xb9\x83\xd6\x84=\x05\x8aVG#\xc7v\xe7\xf9\xe1\x03\x84\xe1l@IHB\x15\x94Q\x91\xf3c\xdc\x06Ws\x94\xa59de\x1c\x1f$9\xde\x9dJ\x03\xbc\xa8\x06\xc8P\x8d\xf3poL\xb0\x1d\xe5\xd8\x8fx1\x86\xc3\xd8\xee\x93\xf7E\xa4?\x89Ds\xbc\x7f\x05\xe9\x93\xd4\xaf\x15s\xcf\xa5\x8c\x11W\x93k\x8b\xd3\xa0\x93\x04\x1c\xd9\x8b/\x9b\xabb}\xf9\xfdz\xd2b\n\x92q\xc8\x91\xca\x144e\xde*\x89N-Q\xea\xe7d6\xee'9\xac\xd3\x86\x83\x9cOxNz1|\x9a\xfbo\x9a\xe0|\xaa\x08B\x9a\xfal\xcf\xcb\xc7\xce\xff\x97\xbd/c\n\xca\xe1|vL+^\x0c\x1f'\x13\xa6\xeb\xe7s\x0d(\xb7\x14r,\x9e\x9e \xe4\"\xa9,Ww\xdb\xdb_W\xc5v\xf3\xf5z\x8d\xe7\xe7\x99}\xdci\xb0\xbe\xa9\xac\xd2\xcb\xd7\xc8L\xa20\xfc\x83\xaen\xbem~\xff,>o\xd67w\xab\x1c\x8bcQ\xfc\xb8\xdc\xde\xce\x9e\x98\xc2\xe9\xf8p5\xb9\xd6\xfc\x1d\x00PK\x07\x08\xe8\xe3K\xb5X\x01\x00\x00/\x02\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x0e\x00	\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8$\xcc\xbb\x0e\x830\x0c\x85\xe1=Oa\x89\xcex\xcf\xd8\xa2R\xd6^\xe6\n*'\xb8\x05\x1b%\x8eP\xdf\xbe\xa2lg8\xffW\xc1I%p\x84\xc0\x13A\xd0\x04\xedy\xd25\xd7\xae\x82\x1b\x11\x8cfK\xf6\x88\x91m,C\xfd\xd2\x19\xdfC*b*\x18\xc3\xf6\xc4\x95?\x8c\xbbRRo\xac\xf2wt\xd9f\xae\xdd\x9e6\x9c<\x1c\xda\xee~y\x1c\x9fMwuF\xf32\xf5F\xd9;\x00\x92\xc8B\x1e\xbef\xee7\x00PK\x07\x08S\xddb\xa7}\x00\x00\x00\x93\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x16\x00	\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8\x00X\x00\xa7\xff#@ def setup_go():\n  uses: actions/setup-go@v5\n  with:\n    go-version: \"^1.14.4\"\n#@ end\n\x03\x00PK\x07\x08\xb0v!\xf0_\x00\x00\x00X\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x13\x00	\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8\x00.\x00\xd1\xff#@data/values\n---\ngit:\n  main_branch: develop\n\x03\x00PK\x07\x08\xe0M\xb2'5\x00\x00\x00.\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1a\x00	\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8\x9c\x8c\xb1\x0e\xc20\x0cD\xf7|\x85\xd5,\xad\x04\xf9\x80N\xf9\x93\xe8\xc0.\xadd\x02\xd4\x0e\x12\x7f\x8f\xdaN\xacL\xa7\xa7\xbb{1\x93>\xc0}\x97?\xee#\xc3\xd1\x9d\xa8\xdbs\x08!fb\x99\xe8\xd9T\xcb*\xaf&\xe6\x85eBS\xb7~\x18\x03\xfdT\x1b\x13]V\xd4\xeb,v\xd0\x996\x07\x1c\xe9\x0dmb\xe9\xb6x\xbac\xa9\xe5\xd8\xed\n\x9b\xff\xba\xc6LR9|\x07\x00PK\x07\x08\xbd\xcf\x85\xf3r\x00\x00\x00\xc3\x00\x00\x00PK\x03\x04\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x1f\x00	\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8l\x90Ok\xc2@\x10\xc5\xef\xfb)\x86\xd5\x83\x81F)\xf4\xb4\xa7h\xf1_\xff\x98B-=.\x9b8j\xcc\xbak\xb3\xb3\x91\"~\xf7\xd2M\xb4\x08\xbd\x0d\xf3\xe6\xfdxo:	h\xabV=~\xb4U\xb9\xd6\xf6\xe8\xfa\xba\xc8\xfa\xdf{\xcd\xef\x80\x1f\xbc\xd6\xb2\xc2/\x8f\x8e\xe4\n\xd7\xcakr<bW\x97#<\xdc8\x1c\x92?\xc8\x8d\xe5\x11cF\xedQ\xc0&P\x19\xe3\xd6p\x01\x9d\x04\xfe\x85\xf6\"\xc6v6s\x82\x01\xe4[\xccKy\x0d\xf4\xbb\x02h`\xdd\xa7t$\x17\xc3\xd7q\xd8U\xde\xb8\xd8\x1a\x01>\xf3\x86|\xac\x15\xa1\xa3 \x85`\x8d3\x06\xef\xd0	P9\x15\xd6\xb8A\xc0[OI\xfd\xd0\xea\x9d\x04.\xb9{\xd1\x8dg\x97U\xde\x905\x83\xa0\xc7M\x97\xa4\xbe\x0fG\x00\xc7\x82\xb6\xa2\x9d\x01\xc8\x96h\x04tO'p\x98WH\xae?\x9d/g\x1f#\xb9L\x9f\xc7\x0b8\x9f[v\xd3\xa5V\xbaX)B\xb86mIh\xea?\xe8t\xf2\x92~\xbe\xcb\xc7t1\x99O\x05t\x9bA\xbe\x0d\x97\xb3\xf6\xa6\xf2\xe6\xf2e\xc8\xb7\x98\x97\xecg\x00PK\x07\x08\xbc\xd6\xc1\x91\x1e\x01\x00\x00\xd5\x01\x00\x00PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(OG\x9f\x0f\x89\x02\x00\x00\xfb\x17\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x00\x00\x00\x00config-schema.jsonUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\x8f#\xdd\xca|\x00\x00\x00\x93\x00\x00\x00\x0e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xd2\x02\x00\x00cue/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbf\xfa\n\xafx\x00\x00\x00\x95\x00\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x93\x03\x00\x00cue/libs/steps.cueUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(~c)*r\x00\x00\x00\xa7\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81T\x04\x00\x00cue/libs/workflows.cueUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(z\xb2\x83_\x0e\x01\x00\x00\x8a\x01\x00\x00\x18\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x13\x05\x00\x00cue/workflows/gflows.cueUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xa0\xb5\xe8\xa7\xd8\x01\x00\x00\x01\x08\x00\x00\x10\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81p\x06\x00\x00deprecations.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(O\x9d\x06\xad\x81\x00\x00\x00\x9a\x00\x00\x00\x15\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x8f\x08\x00\x00gotemplate/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\x00\x0f\xb6\x0ei\x00\x00\x00b\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\\	\x00\x00gotemplate/libs/steps.tmplUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xddW3F\x1c\x00\x00\x00\x15\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x16\n\x00\x00gotemplate/libs/values.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(1p\x9a\x0b_\x00\x00\x00\x99\x00\x00\x00\x1e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x83\n\x00\x00gotemplate/libs/workflows.tmplUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!($\xf2\x05\x07\x0d\x01\x00\x00\xab\x01\x00\x00$\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x817\x0b\x00\x00gotemplate/workflows/gflows.yml.tmplUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xd9\x11\x87n~\x00\x00\x00\x97\x00\x00\x00\x12\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\x9f\x0c\x00\x00jsonnet/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(2r\xa7\xff$\x00\x00\x00\x1d\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81f\x0d\x00\x00jsonnet/libs/git.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xa4\xcd\x015\xb7\x00\x00\x00J\x01\x00\x00\x1c\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xdb\x0d\x00\x00jsonnet/libs/steps.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xdb\x9a\x0eb\x81\x00\x00\x00\xf2\x00\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xe5\x0e\x00\x00jsonnet/libs/workflows.libsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(Piq\xc9p\x01\x00\x00\xb4\x02\x00\x00 \x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xbd\x0f\x00\x00jsonnet/workflows/gflows.jsonnetUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(s\x97\x85\x87~\x00\x00\x00\x94\x00\x00\x00\x0f\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\x84\x11\x00\x00yaml/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(iV\xacG?\x00\x00\x008\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81H\x12\x00\x00yaml/libs/setup-go.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe8\xe3K\xb5X\x01\x00\x00/\x02\x00\x00\x19\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xa4\x81\xd4\x12\x00\x00yaml/workflows/gflows.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(S\xddb\xa7}\x00\x00\x00\x93\x00\x00\x00\x0e\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81|\x14\x00\x00ytt/config.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xb0v!\xf0_\x00\x00\x00X\x00\x00\x00\x16\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81>\x15\x00\x00ytt/libs/steps.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xe0M\xb2'5\x00\x00\x00.\x00\x00\x00\x13\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81\xea\x15\x00\x00ytt/libs/values.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbd\xcf\x85\xf3r\x00\x00\x00\xc3\x00\x00\x00\x1a\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81i\x16\x00\x00ytt/libs/workflows.lib.ymlUT\x05\x00\x01\x80Cm8PK\x01\x02\x14\x03\x14\x00\x08\x00\x08\x00\x00\x00!(\xbc\xd6\xc1\x91\x1e\x01\x00\x00\xd5\x01\x00\x00\x1f\x00	\x00\x00\x00\x00\x00\x00\x00\x00\x00\xb4\x81,\x17\x00\x00ytt/workflows/gflows/gflows.ymlUT\x05\x00\x01\x80Cm8PK\x05\x06\x00\x00\x00\x00\x18\x00\x18\x00Y\x07\x00\x00\xa0\x18\x00\x00\x00\x00"
 		fs.Register(data)
 	}
 	
\ No newline at end of file
@@ -25,6 +25,7 @@ func (container *Container) WorkflowManager() *WorkflowManager {
 		templateEngine := CreateWorkflowEngine(
 			container.FileSystem(),
 			container.Context(),
+			container.ContentReader(),
 			container.ContentWriter(),
 			container.Environment(),
 			container.Logger())
@@ -68,6 +69,18 @@ func (container *Container) Validator() *workflow.Validator {
 	return workflow.NewValidator(container.FileSystem(), container.Context())
 }
 
+func (container *Container) Converter() *Converter {
+	return NewConverter(
+		container.FileSystem(),
+		container.Logger(),
+		container.Styles(),
+		container.Context(),
+		container.ContentReader(),
+		container.ContentWriter(),
+		container.Installer(),
+		container.WorkflowManager())
+}
+
 func (container *Container) Watcher() *Watcher {
 	return NewWatcher(container.WorkflowManager(), container.Context())
 }
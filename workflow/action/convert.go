@@ -0,0 +1,264 @@
+package action
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/io"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/styles"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/spf13/afero"
+)
+
+// Converter - converts templates from the default engine to another engine
+type Converter struct {
+	fs            *afero.Afero
+	logger        *io.Logger
+	styles        *styles.Styles
+	context       *config.GFlowsContext
+	contentReader *content.Reader
+	contentWriter *content.Writer
+	installer     *env.GFlowsLibInstaller
+	manager       *WorkflowManager
+}
+
+func NewConverter(
+	fs *afero.Afero,
+	logger *io.Logger,
+	styles *styles.Styles,
+	context *config.GFlowsContext,
+	contentReader *content.Reader,
+	contentWriter *content.Writer,
+	installer *env.GFlowsLibInstaller,
+	manager *WorkflowManager,
+) *Converter {
+	return &Converter{
+		fs:            fs,
+		logger:        logger,
+		styles:        styles,
+		context:       context,
+		contentReader: contentReader,
+		contentWriter: contentWriter,
+		installer:     installer,
+		manager:       manager,
+	}
+}
+
+type convertedWorkflow struct {
+	definition   *workflow.Definition
+	templatePath string
+}
+
+// ConvertWorkflows - imports the workflows generated by the default engine into templates for the given engine, and
+// makes it the default engine. The new templates must generate the same workflows as the old ones, otherwise nothing
+// is changed. Workflows with an engine override are left as they are.
+func (converter *Converter) ConvertWorkflows(engineName string) error {
+	currentEngine := converter.context.Config.Templates.Engine
+	if engineName == currentEngine {
+		return fmt.Errorf("templates already use the %s engine", engineName)
+	}
+	if !converter.context.Config.IsValidEngine(engineName) {
+		return fmt.Errorf("unexpected engine: %q (expected jsonnet, ytt, cue, gotemplate, yaml or a configured plugin)", engineName)
+	}
+
+	definitions, err := converter.getDefinitionsToConvert()
+	if err != nil {
+		return err
+	}
+
+	// Create the engine as it will be once the config is updated, so that the new templates are checked with the libs
+	// and values they'll be generated with
+	convertedConfig := *converter.context.Config
+	convertedConfig.Templates.Engine = engineName
+	convertedContext := *converter.context
+	convertedContext.Config = &convertedConfig
+	convertedEnv := env.NewGFlowsEnv(converter.fs, converter.installer, &convertedContext, converter.logger)
+	defer convertedEnv.CleanUp()
+	templateEngine := createTemplateEngine(engineName, converter.fs, &convertedContext, converter.contentReader, converter.contentWriter, convertedEnv, converter.logger)
+
+	originals, err := converter.getOriginalTemplates(templateEngine, definitions)
+	if err != nil {
+		return err
+	}
+
+	converted := []*convertedWorkflow{}
+	for _, definition := range definitions {
+		templatePath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: definition.Destination, Definition: definition})
+		if err != nil {
+			converter.removeTemplates(converted, originals)
+			return err
+		}
+		converted = append(converted, &convertedWorkflow{definition: definition, templatePath: templatePath})
+	}
+
+	if err := converter.checkTemplates(templateEngine, converted); err != nil {
+		converter.removeTemplates(converted, originals)
+		return err
+	}
+
+	if err := converter.updateConfig(engineName); err != nil {
+		converter.removeTemplates(converted, originals)
+		return err
+	}
+
+	for _, wf := range converted {
+		converter.logger.Printfln("%11v %s (from %s)", "create", wf.templatePath, wf.definition.Description)
+	}
+	removed := make(map[string]bool)
+	for _, definition := range definitions {
+		if removed[definition.Source] || isConvertedTemplate(definition.Source, converted) {
+			continue
+		}
+		removed[definition.Source] = true
+		if err := converter.fs.RemoveAll(definition.Source); err != nil {
+			return err
+		}
+		converter.logger.Printfln("%11v %s", "remove", definition.Source)
+	}
+	converter.logger.Printfln("%11v %s (templates.engine: %s)", "update", converter.context.ConfigPath, engineName)
+
+	if len(converted) > 0 {
+		converter.logger.Println()
+		converter.logger.Println(converter.styles.StyleWarning("Important:"), "converted workflows need to be updated before validation passes, which may change their formatting.")
+		converter.logger.Println("  ► Run \"gflows update\" to do this now")
+	}
+	libsExist, err := converter.fs.Exists(converter.context.LibsDir())
+	if err == nil && libsExist {
+		converter.logger.Println()
+		converter.logger.Println(converter.styles.StyleWarning("Important:"), converter.context.LibsDir(), "hasn't been converted, remove any libs which are no longer used")
+	}
+	return nil
+}
+
+// getDefinitionsToConvert - returns the definitions generated by the default engine (i.e. those without an engine
+// override), or an error if any of their templates have errors
+func (converter *Converter) getDefinitionsToConvert() ([]*workflow.Definition, error) {
+	definitions, err := converter.manager.GetWorkflowDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	result := []*workflow.Definition{}
+	for _, definition := range definitions {
+		override := converter.context.Config.Templates.Overrides[definition.Name]
+		if override != nil && override.Engine != "" {
+			continue
+		}
+		if !definition.Status.Valid {
+			return nil, fmt.Errorf("error parsing template for %s: %s", definition.Name, strings.Join(definition.Status.Errors, "\n"))
+		}
+		result = append(result, definition)
+	}
+	return result, nil
+}
+
+// checkTemplates - returns an error if any of the converted templates generate different workflows to the originals
+func (converter *Converter) checkTemplates(templateEngine workflow.TemplateEngine, converted []*convertedWorkflow) error {
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+	if err != nil {
+		return err
+	}
+	failed := []string{}
+	for _, wf := range converted {
+		var convertedDefinition *workflow.Definition
+		for _, definition := range definitions {
+			if definition.Name == wf.definition.Name && isTemplateSource(definition.Source, wf.templatePath) {
+				convertedDefinition = definition
+				break
+			}
+		}
+		if convertedDefinition == nil || !convertedDefinition.Status.Valid || !wf.definition.GeneratesSameWorkflow(convertedDefinition) {
+			failed = append(failed, wf.definition.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("converted templates for %s don't generate the same workflows, no changes were made", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (converter *Converter) updateConfig(engineName string) error {
+	configContent, err := converter.fs.ReadFile(converter.context.ConfigPath)
+	if err != nil {
+		return err
+	}
+	updatedContent, err := config.SetTemplateEngine(string(configContent), engineName)
+	if err != nil {
+		return err
+	}
+	return converter.fs.WriteFile(converter.context.ConfigPath, []byte(updatedContent), 0644)
+}
+
+// getOriginalTemplates - returns an error if any of the converted templates would overwrite an existing file, unless
+// it's one of the templates being converted. The content of those templates is returned (keyed by path), so that they
+// can be restored if the conversion fails.
+func (converter *Converter) getOriginalTemplates(templateEngine workflow.TemplateEngine, definitions []*workflow.Definition) (map[string][]byte, error) {
+	originals := make(map[string][]byte)
+	for _, definition := range definitions {
+		templatePath := templateEngine.GetImportedTemplatePath(&workflow.GitHubWorkflow{Path: definition.Destination, Definition: definition})
+		exists, err := converter.fs.Exists(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		if !isSourceOf(templatePath, definitions) {
+			return nil, fmt.Errorf("can't convert %s, %s already exists", definition.Name, templatePath)
+		}
+		content, err := converter.fs.ReadFile(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		originals[templatePath] = content
+	}
+	return originals, nil
+}
+
+// removeTemplates - removes any converted templates (and the directories created for them), or restores the original
+// content of those which replaced one of the original templates
+func (converter *Converter) removeTemplates(converted []*convertedWorkflow, originals map[string][]byte) {
+	for _, wf := range converted {
+		if content, ok := originals[wf.templatePath]; ok {
+			converter.fs.WriteFile(wf.templatePath, content, 0644)
+			continue
+		}
+		converter.fs.Remove(wf.templatePath)
+		for dir := filepath.Dir(wf.templatePath); dir != converter.context.WorkflowsDir() && dir != "."; dir = filepath.Dir(dir) {
+			if empty, err := converter.fs.IsEmpty(dir); err != nil || !empty {
+				break
+			}
+			converter.fs.Remove(dir)
+		}
+	}
+}
+
+// isSourceOf - returns true if the path is the source of any of the definitions
+func isSourceOf(path string, definitions []*workflow.Definition) bool {
+	for _, definition := range definitions {
+		if filepath.Clean(definition.Source) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTemplateSource - returns true if the source of a definition is the template at the given path, or the directory
+// containing it (e.g. for ytt templates)
+func isTemplateSource(source string, templatePath string) bool {
+	return filepath.Clean(source) == filepath.Clean(templatePath) || filepath.Clean(source) == filepath.Dir(templatePath)
+}
+
+// isConvertedTemplate - returns true if the source is (or contains) one of the converted templates, so shouldn't be
+// removed
+func isConvertedTemplate(source string, converted []*convertedWorkflow) bool {
+	for _, wf := range converted {
+		if isTemplateSource(source, wf.templatePath) {
+			return true
+		}
+	}
+	return false
+}
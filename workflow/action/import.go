@@ -1,12 +1,54 @@
 package action
 
-func (manager *WorkflowManager) ImportWorkflows() error {
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/yamlutil"
+	"gopkg.in/yaml.v3"
+)
+
+// ImportWorkflows - imports workflows which don't have templates. If extractCommon is true then steps and jobs which
+// are repeated across the imported workflows are extracted into a lib, and the templates reference them.
+func (manager *WorkflowManager) ImportWorkflows(extractCommon bool) error {
 	imported := 0
 	workflows := manager.GetWorkflows()
+
+	var importer workflow.CommonImporter
+	var common *workflow.CommonElements
+	var libPath string
+	if extractCommon {
+		var ok bool
+		importer, ok = manager.TemplateEngine.(workflow.CommonImporter)
+		if !ok {
+			return fmt.Errorf("the %s engine doesn't support extracting common steps and jobs", manager.context.Config.Templates.Engine)
+		}
+		var err error
+		common, err = manager.findCommonElements(workflows)
+		if err != nil {
+			return err
+		}
+		if !common.IsEmpty() {
+			// Write the lib before any templates, so that nothing is imported if the engine doesn't support it
+			libPath, err = importer.ImportCommonElements(common)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	for _, workflow := range workflows {
 		manager.logger.Println("Found workflow:", workflow.Path)
 		if workflow.Definition == nil {
-			templatePath, err := manager.ImportWorkflow(&workflow)
+			var templatePath string
+			var err error
+			if common != nil && !common.IsEmpty() {
+				templatePath, err = importer.ImportWorkflowWithCommonElements(&workflow, common)
+			} else {
+				templatePath, err = manager.ImportWorkflow(&workflow)
+			}
 			if err != nil {
 				return err
 			}
@@ -17,10 +59,41 @@ func (manager *WorkflowManager) ImportWorkflows() error {
 		}
 	}
 
+	if common != nil {
+		if common.IsEmpty() {
+			manager.logger.Println("No common steps or jobs found")
+		} else {
+			manager.logger.Printfln("Extracted %d common step(s) and %d common job(s) into %s", len(common.Steps), len(common.Jobs), libPath)
+		}
+	}
+
 	if imported > 0 {
 		manager.logger.Println()
-		manager.logger.Println(manager.styles.StyleWarning("Important:"), "imported workflow templates may generate yaml which is ordered differerently from the source. You will need to update the workflows before validation passes.")
+		manager.logger.Println(manager.styles.StyleWarning("Important:"), "imported workflows need to be updated before validation passes.")
 		manager.logger.Println("  ► Run \"gflows update\" to do this now")
 	}
 	return nil
 }
+
+// findCommonElements - finds the steps and jobs repeated across the workflows to be imported. Only workflows which
+// use the default engine are included, since the common lib is only available to the default engine.
+func (manager *WorkflowManager) findCommonElements(workflows []workflow.GitHubWorkflow) (*workflow.CommonElements, error) {
+	workflowData := []*yaml.Node{}
+	for _, wf := range workflows {
+		_, filename := filepath.Split(wf.Path)
+		workflowName := strings.TrimSuffix(filename, filepath.Ext(filename))
+		if wf.Definition != nil || manager.context.Config.GetTemplateEngine(workflowName) != manager.context.Config.Templates.Engine {
+			continue
+		}
+		content, err := manager.fs.ReadFile(wf.Path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yamlutil.ParseWorkflowNode(string(content))
+		if err != nil {
+			return nil, err
+		}
+		workflowData = append(workflowData, data)
+	}
+	return workflow.FindCommonElements(workflowData)
+}
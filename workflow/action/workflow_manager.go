@@ -20,14 +20,39 @@ import (
 	"github.com/spf13/afero"
 )
 
-func CreateWorkflowEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) workflow.TemplateEngine {
+// CreateWorkflowEngine - creates the template engine for the context. If more than one engine is configured then
+// the returned engine aggregates them, with lib paths given separately for each engine.
+func CreateWorkflowEngine(fs *afero.Afero, context *config.GFlowsContext, contentReader *content.Reader, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) workflow.TemplateEngine {
+	engineNames := context.Config.GetTemplateEngines()
+	if len(engineNames) == 1 {
+		return createTemplateEngine(engineNames[0], fs, context, contentReader, contentWriter, env, logger)
+	}
+	engines := make(map[string]workflow.TemplateEngine)
+	for _, engineName := range engineNames {
+		engines[engineName] = createTemplateEngine(engineName, fs, context, contentReader, contentWriter, env.ForEngine(engineName), logger)
+	}
+	return engine.NewMultiTemplateEngine(context, engineNames, engines)
+}
+
+func createTemplateEngine(engineName string, fs *afero.Afero, context *config.GFlowsContext, contentReader *content.Reader, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) workflow.TemplateEngine {
 	var templateEngine workflow.TemplateEngine
-	switch engineName := context.Config.Templates.Engine; engineName {
+	switch engineName {
 	case "jsonnet":
-		templateEngine = engine.NewJsonnetTemplateEngine(fs, context, contentWriter, env)
+		templateEngine = engine.NewJsonnetTemplateEngine(fs, context, contentReader, contentWriter, env)
 	case "ytt":
-		templateEngine = engine.NewYttTemplateEngine(fs, context, contentWriter, env, logger)
+		templateEngine = engine.NewYttTemplateEngine(fs, context, contentReader, contentWriter, env, logger)
+	case "cue":
+		templateEngine = engine.NewCueTemplateEngine(fs, context, contentWriter, env)
+	case "gotemplate":
+		templateEngine = engine.NewGoTemplateEngine(fs, context, contentWriter, env)
+	case "yaml":
+		templateEngine = engine.NewYamlTemplateEngine(fs, context, contentWriter, env)
 	default:
+		if context.Config.GetPluginConfig(engineName) != nil {
+			executor := engine.NewPluginExecutor(context, engineName)
+			templateEngine = engine.NewPluginTemplateEngine(fs, context, contentWriter, env, engineName, executor)
+			break
+		}
 		panic(fmt.Errorf("Unexpected engine: %s", engineName))
 	}
 	return templateEngine
@@ -91,8 +116,28 @@ func (manager *WorkflowManager) GetWorkflows() []workflow.GitHubWorkflow {
 	return gitHubWorkflows
 }
 
-// UpdateWorkflows - update workflow files for the given context
-func (manager *WorkflowManager) UpdateWorkflows() error {
+// GetWorkflowDefinition - returns the definition for the workflow with the given name, or an error if there's no
+// such workflow or its template couldn't be parsed
+func (manager *WorkflowManager) GetWorkflowDefinition(workflowName string) (*workflow.Definition, error) {
+	definitions, err := manager.GetWorkflowDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	for _, definition := range definitions {
+		if definition.Name != workflowName {
+			continue
+		}
+		if !definition.Status.Valid {
+			return nil, fmt.Errorf("error parsing template for %s: %s", workflowName, strings.Join(definition.Status.Errors, "\n"))
+		}
+		return definition, nil
+	}
+	return nil, fmt.Errorf("unknown workflow: %s", workflowName)
+}
+
+// UpdateWorkflows - update workflow files for the given context. Workflows which have been modified since they were
+// generated are only overwritten if force is true.
+func (manager *WorkflowManager) UpdateWorkflows(force bool) error {
 	definitions, err := manager.GetWorkflowDefinitions()
 	if err != nil {
 		return err
@@ -102,14 +147,23 @@ func (manager *WorkflowManager) UpdateWorkflows() error {
 		details := fmt.Sprintf("(from %s)", definition.Description)
 		if definition.Status.Valid {
 			schemaResult := manager.validator.ValidateSchema(definition)
-			if schemaResult.Valid {
-				manager.contentWriter.UpdateFileContent(definition.Destination, definition.Content, details)
-			} else {
+			if !schemaResult.Valid {
 				manager.contentWriter.LogErrors(definition.Destination, details, schemaResult.Errors)
 				valid = false
+			} else {
+				isModified := func(existingContent string) bool {
+					return manager.validator.IsHandEdited(definition, existingContent)
+				}
+				if err := manager.contentWriter.UpdateFileContent(definition.Destination, definition.Content, details, isModified, force); err != nil {
+					valid = false
+				}
 			}
 		} else {
-			manager.contentWriter.LogErrors(definition.Destination, details, definition.Status.Errors)
+			if len(definition.Status.Diagnostics) > 0 {
+				manager.contentWriter.LogDiagnostics(definition.Destination, details, definition.Status.FormatDiagnostics())
+			} else {
+				manager.contentWriter.LogErrors(definition.Destination, details, definition.Status.Errors)
+			}
 			valid = false
 		}
 	}
@@ -132,27 +186,57 @@ func (manager *WorkflowManager) ValidateWorkflows(showDiff bool) error {
 		if !definition.Status.Valid {
 			manager.logger.Println(manager.styles.StyleError("FAILED"))
 			manager.logger.Println("  Error parsing template:")
-			manager.logger.PrintStatusErrors(definition.Status.Errors, false)
+			if len(definition.Status.Diagnostics) > 0 {
+				manager.logger.PrintDiagnostics(definition.Status.FormatDiagnostics())
+			} else {
+				manager.logger.PrintStatusErrors(definition.Status.Errors, false)
+			}
 			valid = false
 			continue
 		}
 
-		schemaResult := manager.validator.ValidateSchema(definition)
-		if !schemaResult.Valid {
-			manager.logger.Println(manager.styles.StyleError("FAILED"))
-			manager.logger.Println("  Schema validation failed:")
-			manager.logger.PrintStatusErrors(schemaResult.Errors, false)
-			valid = false
+		failed := false
+		printFailed := func() {
+			if !failed {
+				manager.logger.Println(manager.styles.StyleError("FAILED"))
+				failed = true
+			}
+		}
+
+		checks := []struct {
+			description string
+			result      workflow.ValidationResult
+		}{
+			{"Schema validation failed:", manager.validator.ValidateSchema(definition)},
+			{"Permissions check failed:", manager.validator.ValidatePermissions(definition)},
+			{"Matrix check failed:", manager.validator.ValidateMatrices(definition)},
+			{"Schedule check failed:", manager.validator.ValidateSchedules(definition)},
+			{"Inputs check failed:", manager.validator.ValidateInputs(definition)},
+			{"Workflow call check failed:", manager.validator.ValidateWorkflowCalls(definition, definitions)},
+			{"Action check failed:", manager.validator.ValidateActions(definition)},
+			{"Shell check failed:", manager.validator.ValidateShellScripts(definition)},
+			{"Steps check failed:", manager.validator.ValidateSteps(definition)},
+			{"Deprecation check failed:", manager.validator.ValidateDeprecations(definition)},
+		}
+		results := []workflow.ValidationResult{}
+		for _, check := range checks {
+			if !check.result.Valid {
+				printFailed()
+				manager.logger.Println("  " + check.description)
+				manager.logger.PrintStatusErrors(check.result.Errors, false)
+			}
+			results = append(results, check.result)
 		}
 
 		contentResult := manager.validator.ValidateContent(definition)
 		if !contentResult.Valid {
-			if schemaResult.Valid { // otherwise we'll duplicate the failure message
-				manager.logger.Println(manager.styles.StyleError("FAILED"))
-			}
+			printFailed()
 			manager.logger.Println("  " + contentResult.Errors[0])
-			manager.logger.Println("  ► Run \"gflows workflow update\" to update")
-			valid = false
+			if contentResult.HandEdited {
+				manager.logger.Println("  ► Move any changes into the template, then run \"gflows update --force\" to overwrite")
+			} else {
+				manager.logger.Println("  ► Run \"gflows update\" to update")
+			}
 
 			if showDiff {
 				fpatch, err := diff.CreateFilePatch(contentResult.ActualContent, definition.Content)
@@ -168,10 +252,14 @@ func (manager *WorkflowManager) ValidateWorkflows(showDiff bool) error {
 			}
 		}
 
-		if schemaResult.Valid && contentResult.Valid {
+		if failed {
+			valid = false
+		} else {
 			manager.logger.Println(manager.styles.StyleOK("OK"))
-			for _, err := range append(schemaResult.Errors, contentResult.Errors...) {
-				manager.logger.Printf("  Warning: %s\n", err)
+			for _, result := range append(results, contentResult) {
+				for _, err := range result.Errors {
+					manager.logger.Printf("  Warning: %s\n", err)
+				}
 			}
 		}
 	}
@@ -24,7 +24,7 @@ func newTestWorkflowManager() (*afero.Afero, *bytes.Buffer, *WorkflowManager) {
 	container := content.NewContainer(ioContainer, httpClient)
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
 	env := env.NewGFlowsEnv(fs, installer, context, container.Logger())
-	templateEngine := CreateWorkflowEngine(fs, context, container.ContentWriter(), env, container.Logger())
+	templateEngine := CreateWorkflowEngine(fs, context, container.ContentReader(), container.ContentWriter(), env, container.Logger())
 	return fs, out, NewWorkflowManager(
 		fs,
 		container.Logger(),
@@ -87,7 +87,7 @@ Checking test ... FAILED
   Schema validation failed:
   ► (root): jobs is required
   Workflow missing for "test" (expected workflow at .github/workflows/test.yml)
-  ► Run "gflows workflow update" to update
+  ► Run "gflows update" to update
 `,
 		},
 		{
@@ -99,7 +99,7 @@ Checking test ... FAILED
 			expectedOutput: `
 Checking test ... FAILED
   Workflow missing for "test" (expected workflow at .github/workflows/test.yml)
-  ► Run "gflows workflow update" to update
+  ► Run "gflows update" to update
 `,
 		},
 		{
@@ -112,7 +112,7 @@ Checking test ... FAILED
 			expectedOutput: `
 Checking test ... FAILED
   Content is out of date for "test" (.github/workflows/test.yml)
-  ► Run "gflows workflow update" to update
+  ► Run "gflows update" to update
 `,
 		},
 		{
@@ -164,13 +164,13 @@ Checking test ... OK
 	//   Schema validation failed:
 	//   ► (root): jobs is required
 	//   Workflow missing for "test" (expected workflow at .github/workflows/test.yml)
-	//   ► Run "gflows workflow update" to update
+	//   ► Run "gflows update" to update
 	// Checking test ... FAILED
 	//   Workflow missing for "test" (expected workflow at .github/workflows/test.yml)
-	//   ► Run "gflows workflow update" to update
+	//   ► Run "gflows update" to update
 	// Checking test ... FAILED
 	//   Content is out of date for "test" (.github/workflows/test.yml)
-	//   ► Run "gflows workflow update" to update
+	//   ► Run "gflows update" to update
 	// Checking test ... OK
 	// `
 	// 	fmt.Println("out:", out.String())
@@ -183,7 +183,7 @@ func TestUpdateWorkflows(t *testing.T) {
 	fs.WriteFile(".gflows/workflows/test2.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
 	fs.WriteFile(".github/workflows/test.yml", []byte("out of date workflow"), 0644)
 
-	err := workflowManager.UpdateWorkflows()
+	err := workflowManager.UpdateWorkflows(false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, strings.Join([]string{
@@ -195,3 +195,33 @@ func TestUpdateWorkflows(t *testing.T) {
 	test2Content, _ := fs.ReadFile(".github/workflows/test2.yml")
 	assert.Equal(t, fixtures.ExampleWorkflow("test2.jsonnet"), string(test2Content))
 }
+
+func TestUpdateHandEditedWorkflows(t *testing.T) {
+	fs, out, workflowManager := newTestWorkflowManager()
+	editedContent := fixtures.ExampleWorkflow("test.jsonnet") + "# a hand edit\n"
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".github/workflows/test.yml", []byte(editedContent), 0644)
+
+	err := workflowManager.UpdateWorkflows(false)
+
+	assert.EqualError(t, err, "errors encountered generating workflows")
+	assert.Equal(t, strings.Join([]string{
+		"      error .github/workflows/test.yml (from .gflows/workflows/test.jsonnet)",
+		"  ► Workflow has been modified since it was generated, run with --force to overwrite it",
+	}, "\n")+"\n", out.String())
+	testContent, _ := fs.ReadFile(".github/workflows/test.yml")
+	assert.Equal(t, editedContent, string(testContent))
+}
+
+func TestForceUpdateHandEditedWorkflows(t *testing.T) {
+	fs, out, workflowManager := newTestWorkflowManager()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".github/workflows/test.yml", []byte(fixtures.ExampleWorkflow("test.jsonnet")+"# a hand edit\n"), 0644)
+
+	err := workflowManager.UpdateWorkflows(true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "     update .github/workflows/test.yml (from .gflows/workflows/test.jsonnet)\n", out.String())
+	testContent, _ := fs.ReadFile(".github/workflows/test.yml")
+	assert.Equal(t, fixtures.ExampleWorkflow("test.jsonnet"), string(testContent))
+}
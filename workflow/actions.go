@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/yamlutil"
+)
+
+var actionFileNames = []string{"action.yml", "action.yaml"}
+
+// checkActions - returns errors for any steps which use local actions that don't exist, or which pass inputs the
+// action doesn't declare or omit inputs it requires
+func (validator *Validator) checkActions(workflowJSON interface{}) []string {
+	errors := []string{}
+	jobs := getJobs(workflowJSON)
+	for _, jobName := range sortedKeys(jobs) {
+		for i, step := range getSteps(jobs[jobName]) {
+			stepMap, _ := step.(map[string]interface{})
+			uses, _ := stepMap["uses"].(string)
+			if !strings.HasPrefix(uses, "./") {
+				continue
+			}
+			path := fmt.Sprintf("jobs.%s.steps[%d]", jobName, i)
+
+			action, err := validator.loadAction(uses)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s.uses: %s", path, err))
+				continue
+			}
+
+			inputs, _ := action["inputs"].(map[string]interface{})
+			with, _ := stepMap["with"].(map[string]interface{})
+			for _, name := range sortedKeys(with) {
+				if _, ok := inputs[name]; !ok {
+					errors = append(errors, fmt.Sprintf("%s.with.%s: input %q is not declared by %s", path, name, name, uses))
+				}
+			}
+			for _, name := range sortedKeys(inputs) {
+				input, _ := inputs[name].(map[string]interface{})
+				_, hasDefault := input["default"]
+				if input["required"] == true && !hasDefault {
+					if _, ok := with[name]; !ok {
+						errors = append(errors, fmt.Sprintf("%s.with: required input %q for %s is missing", path, name, uses))
+					}
+				}
+			}
+		}
+	}
+	return errors
+}
+
+// loadAction - loads the metadata for a local action, from either action.yml or action.yaml
+func (validator *Validator) loadAction(uses string) (map[string]interface{}, error) {
+	dir := validator.resolveRepoPath(uses)
+	for _, fileName := range actionFileNames {
+		path := filepath.Join(dir, fileName)
+		exists, err := validator.fs.Exists(path)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		data, err := validator.fs.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		json, err := yamlutil.YamlToJson(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", filepath.Join(uses, fileName), err)
+		}
+		action, _ := json.(map[string]interface{})
+		return action, nil
+	}
+	return nil, fmt.Errorf("action %s does not exist (expected %s)", uses, strings.Join(actionFileNames, " or "))
+}
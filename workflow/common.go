@@ -0,0 +1,376 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CommonImporter - implemented by template engines which can extract the steps and jobs that imported workflows have
+// in common into a lib
+type CommonImporter interface {
+	// ImportCommonElements - writes the common elements to a lib, returns the path to the lib.
+	ImportCommonElements(common *CommonElements) (string, error)
+
+	// ImportWorkflowWithCommonElements - imports a workflow which references the common elements in the lib, returns
+	// the path to the new template.
+	ImportWorkflowWithCommonElements(workflow *GitHubWorkflow, common *CommonElements) (string, error)
+}
+
+// CommonElement - a step or job which is repeated across workflows
+type CommonElement struct {
+	// Kind - either "steps" or "jobs"
+	Kind string
+	// Name - an identifier for the element, unique across all the common elements
+	Name  string
+	Value *yaml.Node
+}
+
+// CommonElements - the steps and jobs repeated across workflows
+type CommonElements struct {
+	Steps    []*CommonElement
+	Jobs     []*CommonElement
+	elements map[string]*CommonElement
+}
+
+var nonIdentifierRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// reservedWords - the Jsonnet and Starlark (ytt) keywords, which can't be used as identifiers
+var reservedWords = map[string]bool{
+	// Jsonnet
+	"assert": true, "else": true, "error": true, "false": true, "for": true, "function": true, "if": true,
+	"import": true, "importbin": true, "importstr": true, "in": true, "local": true, "null": true, "self": true,
+	"super": true, "tailstrict": true, "then": true, "true": true,
+	// Starlark, including the words it reserves for future use
+	"and": true, "as": true, "async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "except": true, "finally": true, "from": true, "global": true,
+	"is": true, "lambda": true, "load": true, "nonlocal": true, "not": true, "or": true, "pass": true,
+	"raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// FindCommonElements - finds the jobs which appear in more than one of the given workflows, and then the steps which
+// appear in more than one of the remaining jobs' workflows. Elements are returned in the order they first appear, and
+// are compared by value (so formatting, comments and the order of keys are ignored).
+func FindCommonElements(workflows []*yaml.Node) (*CommonElements, error) {
+	common := &CommonElements{elements: make(map[string]*CommonElement)}
+	names := make(map[string]bool)
+
+	jobs, err := countElements(workflows, func(workflow *yaml.Node) ([]*yaml.Node, error) {
+		jobs := []*yaml.Node{}
+		for _, job := range getJobNodes(workflow) {
+			jobs = append(jobs, job.value)
+		}
+		return jobs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if job.count < 2 {
+			continue
+		}
+		element := &CommonElement{Kind: "jobs", Name: uniqueName(job.name, names), Value: job.value}
+		common.Jobs = append(common.Jobs, element)
+		common.elements[job.key] = element
+	}
+
+	steps, err := countElements(workflows, func(workflow *yaml.Node) ([]*yaml.Node, error) {
+		steps := []*yaml.Node{}
+		for _, job := range getJobNodes(workflow) {
+			element, err := common.find("jobs", job.value)
+			if err != nil {
+				return nil, err
+			}
+			if element != nil {
+				continue
+			}
+			steps = append(steps, getStepNodes(job.value)...)
+		}
+		return steps, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range steps {
+		if step.count < 2 {
+			continue
+		}
+		element := &CommonElement{Kind: "steps", Name: uniqueName(getStepName(step.value), names), Value: step.value}
+		common.Steps = append(common.Steps, element)
+		common.elements[step.key] = element
+	}
+
+	return common, nil
+}
+
+// IsEmpty - returns true if there are no common elements
+func (common *CommonElements) IsEmpty() bool {
+	return len(common.Steps) == 0 && len(common.Jobs) == 0
+}
+
+// ReplaceCommonElements - returns a copy of the workflow with each of its common jobs and steps replaced by the node
+// returned by reference, together with the elements that were replaced (in order). The comments on the replaced nodes
+// are kept on the references. The original isn't modified.
+func (common *CommonElements) ReplaceCommonElements(workflow *yaml.Node, reference func(element *CommonElement) *yaml.Node) (*yaml.Node, []*CommonElement, error) {
+	referenced := []*CommonElement{}
+	found := make(map[*CommonElement]bool)
+	addReference := func(element *CommonElement) *yaml.Node {
+		if !found[element] {
+			found[element] = true
+			referenced = append(referenced, element)
+		}
+		return reference(element)
+	}
+
+	result := copyNode(workflow)
+	root := result
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = copyNode(root.Content[0])
+		result.Content[0] = root
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "jobs" || root.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		jobs := copyNode(root.Content[i+1])
+		root.Content[i+1] = jobs
+		for j := 1; j < len(jobs.Content); j += 2 {
+			element, err := common.find("jobs", jobs.Content[j])
+			if err != nil {
+				return nil, nil, err
+			}
+			if element != nil {
+				reference := addReference(element)
+				if reference.LineComment != "" && jobs.Content[j-1].LineComment != "" {
+					// the reference uses the line comment (e.g. for a ytt annotation), so move the key's comment above it
+					key := copyNode(jobs.Content[j-1])
+					key.HeadComment = joinComments(key.HeadComment, key.LineComment)
+					key.LineComment = ""
+					jobs.Content[j-1] = key
+				}
+				job := jobs.Content[j]
+				jobs.Content[j] = keepComments(reference, job.HeadComment, job.LineComment, job.FootComment)
+				continue
+			}
+			job, err := common.replaceSteps(jobs.Content[j], addReference)
+			if err != nil {
+				return nil, nil, err
+			}
+			jobs.Content[j] = job
+		}
+	}
+	return result, referenced, nil
+}
+
+func (common *CommonElements) replaceSteps(job *yaml.Node, reference func(element *CommonElement) *yaml.Node) (*yaml.Node, error) {
+	if job.Kind != yaml.MappingNode {
+		return job, nil
+	}
+	result := copyNode(job)
+	for i := 0; i+1 < len(result.Content); i += 2 {
+		if result.Content[i].Value != "steps" || result.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		steps := copyNode(result.Content[i+1])
+		result.Content[i+1] = steps
+		for j, step := range steps.Content {
+			element, err := common.find("steps", step)
+			if err != nil {
+				return nil, err
+			}
+			if element != nil {
+				// comments on the first line of a step belong to its first key and value
+				headComment, lineComment := step.HeadComment, step.LineComment
+				if len(step.Content) >= 2 {
+					headComment = joinComments(headComment, step.Content[0].HeadComment)
+					lineComment = joinComments(lineComment, step.Content[0].LineComment, step.Content[1].LineComment)
+				}
+				steps.Content[j] = keepComments(reference(element), headComment, lineComment, step.FootComment)
+			}
+		}
+	}
+	return result, nil
+}
+
+// keepComments - adds the comments of a replaced node to its reference. If the reference has its own line comment
+// (e.g. for a ytt annotation), then the line comment is kept on the line above instead.
+func keepComments(reference *yaml.Node, headComment string, lineComment string, footComment string) *yaml.Node {
+	if reference.LineComment == "" {
+		reference.LineComment = lineComment
+	} else {
+		headComment = joinComments(headComment, lineComment)
+	}
+	reference.HeadComment = joinComments(reference.HeadComment, headComment)
+	reference.FootComment = joinComments(reference.FootComment, footComment)
+	return reference
+}
+
+// joinComments - joins the non-empty comments onto separate lines
+func joinComments(comments ...string) string {
+	nonEmpty := []string{}
+	for _, comment := range comments {
+		if comment != "" {
+			nonEmpty = append(nonEmpty, comment)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
+func (common *CommonElements) find(kind string, value *yaml.Node) (*CommonElement, error) {
+	key, err := elementKey(value)
+	if err != nil {
+		return nil, err
+	}
+	element := common.elements[key]
+	if element == nil || element.Kind != kind {
+		return nil, nil
+	}
+	return element, nil
+}
+
+type elementCount struct {
+	key   string
+	name  string
+	value *yaml.Node
+	count int
+}
+
+// countElements - counts the number of workflows each element appears in. Jobs are named after the first id they're
+// given.
+func countElements(workflows []*yaml.Node, getElements func(workflow *yaml.Node) ([]*yaml.Node, error)) ([]*elementCount, error) {
+	counts := []*elementCount{}
+	countsByKey := make(map[string]*elementCount)
+	for _, workflow := range workflows {
+		names := make(map[*yaml.Node]string)
+		for _, job := range getJobNodes(workflow) {
+			names[job.value] = job.name
+		}
+		elements, err := getElements(workflow)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]bool)
+		for _, value := range elements {
+			if value.Kind != yaml.MappingNode {
+				continue
+			}
+			key, err := elementKey(value)
+			if err != nil {
+				return nil, err
+			}
+			if found[key] {
+				continue
+			}
+			found[key] = true
+			count := countsByKey[key]
+			if count == nil {
+				count = &elementCount{key: key, name: names[value], value: value}
+				countsByKey[key] = count
+				counts = append(counts, count)
+			}
+			count.count++
+		}
+	}
+	return counts, nil
+}
+
+// elementKey - returns a key which is the same for elements with the same value
+func elementKey(node *yaml.Node) (string, error) {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return "", fmt.Errorf("line %d: %s", node.Line, err)
+	}
+	key, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("line %d: %s", node.Line, err)
+	}
+	return string(key), nil
+}
+
+func copyNode(node *yaml.Node) *yaml.Node {
+	result := *node
+	result.Content = append([]*yaml.Node{}, node.Content...)
+	return &result
+}
+
+type jobNode struct {
+	name  string
+	value *yaml.Node
+}
+
+func getJobNodes(workflow *yaml.Node) []jobNode {
+	if workflow.Kind == yaml.DocumentNode && len(workflow.Content) > 0 {
+		workflow = workflow.Content[0]
+	}
+	jobs := []jobNode{}
+	if workflow.Kind != yaml.MappingNode {
+		return jobs
+	}
+	for i := 0; i+1 < len(workflow.Content); i += 2 {
+		if workflow.Content[i].Value != "jobs" || workflow.Content[i+1].Kind != yaml.MappingNode {
+			continue
+		}
+		jobsNode := workflow.Content[i+1]
+		for j := 0; j+1 < len(jobsNode.Content); j += 2 {
+			jobs = append(jobs, jobNode{name: jobsNode.Content[j].Value, value: jobsNode.Content[j+1]})
+		}
+	}
+	return jobs
+}
+
+func getStepNodes(job *yaml.Node) []*yaml.Node {
+	if job.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(job.Content); i += 2 {
+		if job.Content[i].Value == "steps" && job.Content[i+1].Kind == yaml.SequenceNode {
+			return job.Content[i+1].Content
+		}
+	}
+	return nil
+}
+
+// getStepName - returns a name for a step from its name, the action it uses or the command it runs
+func getStepName(step *yaml.Node) string {
+	fields := make(map[string]string)
+	for i := 0; i+1 < len(step.Content); i += 2 {
+		fields[step.Content[i].Value] = step.Content[i+1].Value
+	}
+	if name := fields["name"]; name != "" {
+		return name
+	}
+	if uses := fields["uses"]; uses != "" {
+		// e.g. actions/setup-go@v2 => setup-go
+		action := strings.Split(uses, "@")[0]
+		return action[strings.LastIndex(action, "/")+1:]
+	}
+	if run := fields["run"]; run != "" {
+		// e.g. "make test" => make_test
+		words := strings.Fields(strings.Split(strings.TrimSpace(run), "\n")[0])
+		if len(words) > 3 {
+			words = words[:3]
+		}
+		return strings.Join(words, " ")
+	}
+	return "step"
+}
+
+// uniqueName - converts the name to an identifier, numbered if it's already taken. Reserved words are given an "_"
+// suffix (e.g. import_), since they can't be used as identifiers in either Jsonnet or ytt.
+func uniqueName(name string, names map[string]bool) string {
+	identifier := strings.Trim(nonIdentifierRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	unique := identifier
+	if reservedWords[identifier] {
+		unique = identifier + "_"
+	}
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", identifier, i)
+	}
+	names[unique] = true
+	return unique
+}
@@ -0,0 +1,244 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func parseWorkflows(t *testing.T, contents ...string) []*yaml.Node {
+	workflows := []*yaml.Node{}
+	for _, content := range contents {
+		workflow, err := yamlutil.ParseWorkflowNode(content)
+		assert.NoError(t, err)
+		workflows = append(workflows, workflow)
+	}
+	return workflows
+}
+
+const buildWorkflow = `
+name: build
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v2
+    - run: make lint
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v2
+    - name: Run Tests
+      run: make test
+`
+
+const releaseWorkflow = `
+name: release
+on: push
+jobs:
+  lint:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v2
+    - run: make lint
+  release:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v2
+    - name: Run Tests
+      run: make test
+    - run: make release
+`
+
+func TestFindCommonElements(t *testing.T) {
+	common, err := FindCommonElements(parseWorkflows(t, buildWorkflow, releaseWorkflow))
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"lint"}, getElementNames(common.Jobs))
+	assert.Equal(t, []string{"checkout", "run_tests"}, getElementNames(common.Steps))
+	assert.Equal(t, "actions/checkout@v2", yamlutil.GetMappingValue(common.Steps[0].Value, "uses").Value)
+}
+
+func TestFindCommonElementsInOneWorkflow(t *testing.T) {
+	// Elements are only common if they're repeated across workflows
+	common, err := FindCommonElements(parseWorkflows(t, buildWorkflow))
+
+	assert.NoError(t, err)
+	assert.True(t, common.IsEmpty())
+}
+
+func TestCommonElementNames(t *testing.T) {
+	common, err := FindCommonElements(parseWorkflows(t, `
+jobs:
+  test:
+    steps:
+    - run: make test
+    - uses: actions/setup-go@v2
+    - uses: docker://alpine:3.8
+  build:
+    steps:
+    - name: Checkout
+      uses: actions/checkout@v2
+`, `
+jobs:
+  test:
+    steps:
+    - run: make test
+    - uses: actions/setup-go@v2
+    - uses: docker://alpine:3.8
+  build:
+    steps:
+    - name: Checkout
+      uses: actions/checkout@v2
+    - name: Build
+      run: make build
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test"}, getElementNames(common.Jobs))
+	assert.Equal(t, []string{"checkout"}, getElementNames(common.Steps))
+
+	names := make(map[string]bool)
+	assert.Equal(t, "make_test", uniqueName(getStepName(parseStep(t, "run: make test")), names))
+	assert.Equal(t, "setup_go", uniqueName(getStepName(parseStep(t, "uses: actions/setup-go@v2")), names))
+	assert.Equal(t, "alpine_3_8", uniqueName(getStepName(parseStep(t, "uses: docker://alpine:3.8")), names))
+	assert.Equal(t, "setup_go_2", uniqueName("setup-go", names))
+	assert.Equal(t, "_3", uniqueName("3", names))
+	assert.Equal(t, "import_", uniqueName("Import", names))
+	assert.Equal(t, "import_2", uniqueName("import", names))
+	assert.Equal(t, "pass_", uniqueName("pass", names))
+}
+
+func TestReplaceCommonElements(t *testing.T) {
+	workflows := parseWorkflows(t, buildWorkflow, releaseWorkflow)
+	common, err := FindCommonElements(workflows)
+	assert.NoError(t, err)
+
+	workflow, referenced, err := common.ReplaceCommonElements(workflows[0], func(element *CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: element.Kind + "." + element.Name}
+	})
+
+	assert.NoError(t, err)
+	content, _ := yamlutil.EncodeNode(workflow)
+	assert.Equal(t, `name: build
+"on": push
+jobs:
+  lint: jobs.lint
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - steps.checkout
+    - steps.run_tests
+`, string(content))
+	assert.Equal(t, []string{"lint", "checkout", "run_tests"}, getElementNames(referenced))
+}
+
+func TestReplaceCommonElementsKeepsComments(t *testing.T) {
+	workflows := parseWorkflows(t, `
+jobs:
+  # lints the code
+  lint: # the linter
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v2
+    - run: make lint
+  test:
+    steps:
+    # check out the code first
+    - uses: actions/checkout@v2 # pinned
+    - run: make test
+`, releaseWorkflow)
+	common, err := FindCommonElements(workflows)
+	assert.NoError(t, err)
+
+	workflow, _, err := common.ReplaceCommonElements(workflows[0], func(element *CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: element.Kind + "." + element.Name}
+	})
+	assert.NoError(t, err)
+	content, _ := yamlutil.EncodeNode(workflow)
+	assert.Equal(t, `jobs:
+  # lints the code
+  lint: jobs.lint # the linter
+  test:
+    steps:
+    # check out the code first
+    - steps.checkout # pinned
+    - run: make test
+`, string(content))
+
+	// if the reference has a line comment then the original one is kept above it
+	workflow, _, err = common.ReplaceCommonElements(workflows[0], func(element *CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", LineComment: "#@ " + element.Name + "()"}
+	})
+	assert.NoError(t, err)
+	content, _ = yamlutil.EncodeNode(workflow)
+	assert.Equal(t, `jobs:
+  # lints the code
+  # the linter
+  lint: #@ lint()
+  test:
+    steps:
+    # check out the code first
+    # pinned
+    - #@ checkout()
+    - run: make test
+`, string(content))
+}
+
+func TestCommonElementsWithComplexKeys(t *testing.T) {
+	workflows := parseWorkflows(t, `
+jobs:
+  test:
+    env:
+      ? [x]
+      : y
+`, buildWorkflow)
+
+	_, err := FindCommonElements(workflows)
+	assert.EqualError(t, err, `line 4: yaml: invalid map key: []interface {}{"x"}`)
+
+	common, err := FindCommonElements(parseWorkflows(t, buildWorkflow, releaseWorkflow))
+	assert.NoError(t, err)
+	_, _, err = common.ReplaceCommonElements(workflows[0], func(element *CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: element.Kind + "." + element.Name}
+	})
+	assert.EqualError(t, err, `line 4: yaml: invalid map key: []interface {}{"x"}`)
+}
+
+func TestCommonElementsIgnoreFormatting(t *testing.T) {
+	common, err := FindCommonElements(parseWorkflows(t, `
+jobs:
+  test:
+    steps:
+    - uses: actions/setup-go@v2 # set up go
+      with: { go-version: "1.14" }
+`, `
+jobs:
+  test:
+    steps:
+    - with:
+        go-version: '1.14'
+      uses: actions/setup-go@v2
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test"}, getElementNames(common.Jobs))
+}
+
+func parseStep(t *testing.T, content string) *yaml.Node {
+	step, err := yamlutil.ParseWorkflowNode(content)
+	assert.NoError(t, err)
+	return step.Content[0]
+}
+
+func getElementNames(elements []*CommonElement) []string {
+	names := []string{}
+	for _, element := range elements {
+		names = append(names, element.Name)
+	}
+	return names
+}
@@ -0,0 +1,304 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinScheduleInterval - the shortest interval (in minutes) GitHub supports for scheduled workflows
+const MinScheduleInterval = 5
+
+type cronField struct {
+	name   string
+	min    int
+	max    int
+	values []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, values: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 6, values: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+var monthNames = []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+// CronSchedule - a parsed POSIX cron expression, as used by on.schedule triggers
+type CronSchedule struct {
+	Expression string
+	fields     [5][]int
+	wildcards  [5]bool
+}
+
+// ParseCron - parses a cron expression with the five fields GitHub supports (minute, hour, day of month, month and
+// day of week)
+func ParseCron(expression string) (*CronSchedule, error) {
+	parts := strings.Fields(expression)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("expected 5 fields but found %d", len(parts))
+	}
+	schedule := &CronSchedule{Expression: expression}
+	for i, part := range parts {
+		values, err := cronFields[i].parse(part)
+		if err != nil {
+			return nil, err
+		}
+		schedule.fields[i] = values
+		schedule.wildcards[i] = part == "*"
+	}
+	return schedule, nil
+}
+
+func (field cronField) parse(part string) ([]int, error) {
+	set := make(map[int]bool)
+	for _, item := range strings.Split(part, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangePart = item[:i]
+			var err error
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid %s field %q (invalid step)", field.name, item)
+			}
+		}
+
+		var from, to int
+		if rangePart == "*" {
+			from, to = field.min, field.max
+		} else {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			from, err = field.parseValue(bounds[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s field %q (%s)", field.name, item, err)
+			}
+			to = from
+			if len(bounds) == 2 {
+				to, err = field.parseValue(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s field %q (%s)", field.name, item, err)
+				}
+			} else if step > 1 {
+				// e.g. 5/15 is equivalent to 5-59/15
+				to = field.max
+			}
+			if from > to {
+				return nil, fmt.Errorf("invalid %s field %q (range is backwards)", field.name, item)
+			}
+		}
+
+		for value := from; value <= to; value += step {
+			if field.name == "day of week" && value == 7 {
+				// 7 is an alias for Sunday
+				set[0] = true
+				continue
+			}
+			set[value] = true
+		}
+	}
+
+	values := []int{}
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+func (field cronField) parseValue(value string) (int, error) {
+	for i, name := range field.values {
+		if strings.EqualFold(value, name) {
+			return field.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number between %d and %d", field.min, field.max)
+	}
+	if field.name == "day of week" && n == 7 {
+		// 7 is an alias for Sunday, which is kept as 7 so that it can end a range (e.g. 5-7)
+		return n, nil
+	}
+	if n < field.min || n > field.max {
+		return 0, fmt.Errorf("expected a number between %d and %d", field.min, field.max)
+	}
+	return n, nil
+}
+
+func (schedule *CronSchedule) minutes() []int     { return schedule.fields[0] }
+func (schedule *CronSchedule) hours() []int       { return schedule.fields[1] }
+func (schedule *CronSchedule) daysOfMonth() []int { return schedule.fields[2] }
+func (schedule *CronSchedule) months() []int      { return schedule.fields[3] }
+func (schedule *CronSchedule) daysOfWeek() []int  { return schedule.fields[4] }
+
+// MinInterval - returns the shortest interval in minutes between two runs of the schedule
+func (schedule *CronSchedule) MinInterval() int {
+	minutes := schedule.minutes()
+	interval := 60 - minutes[len(minutes)-1] + minutes[0]
+	if !schedule.hasConsecutiveHours() {
+		// the wrap around to the next hour only applies if the next hour is also scheduled
+		interval = 24 * 60
+	}
+	for i := 1; i < len(minutes); i++ {
+		if gap := minutes[i] - minutes[i-1]; gap < interval {
+			interval = gap
+		}
+	}
+	return interval
+}
+
+func (schedule *CronSchedule) hasConsecutiveHours() bool {
+	hours := schedule.hours()
+	for i, hour := range hours {
+		next := hours[(i+1)%len(hours)]
+		if next == (hour+1)%24 {
+			return true
+		}
+	}
+	return false
+}
+
+// Next - returns the next count times (in UTC) the schedule will run after the given time
+func (schedule *CronSchedule) Next(after time.Time, count int) []time.Time {
+	times := []time.Time{}
+	after = after.UTC()
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+	// Every valid schedule runs at least once every 4 years (e.g. on February 29th)
+	for days := 0; days < 366*4+1 && len(times) < count; days++ {
+		if schedule.matchesDay(day) {
+			for _, hour := range schedule.hours() {
+				for _, minute := range schedule.minutes() {
+					t := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+					if t.After(after) && len(times) < count {
+						times = append(times, t)
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return times
+}
+
+func (schedule *CronSchedule) matchesDay(day time.Time) bool {
+	if !containsInt(schedule.months(), int(day.Month())) {
+		return false
+	}
+	matchesDayOfMonth := containsInt(schedule.daysOfMonth(), day.Day())
+	matchesDayOfWeek := containsInt(schedule.daysOfWeek(), int(day.Weekday()))
+	domWildcard, dowWildcard := schedule.wildcards[2], schedule.wildcards[4]
+	if !domWildcard && !dowWildcard {
+		// if both are restricted, cron runs when either matches
+		return matchesDayOfMonth || matchesDayOfWeek
+	}
+	return matchesDayOfMonth && matchesDayOfWeek
+}
+
+// Describe - returns a human readable description of the schedule
+func (schedule *CronSchedule) Describe() string {
+	description := schedule.describeTime()
+	if days := schedule.describeDays(); days != "" {
+		description = description + ", " + days
+	}
+	if !schedule.wildcards[3] {
+		names := []string{}
+		for _, month := range schedule.months() {
+			names = append(names, monthNames[month-1])
+		}
+		description = description + ", in " + joinDescriptions(names)
+	}
+	return description + " (UTC)"
+}
+
+func (schedule *CronSchedule) describeTime() string {
+	minutes, hours := schedule.minutes(), schedule.hours()
+	allHours := len(hours) == 24
+	if len(minutes) == 1 && len(hours) <= 3 {
+		times := []string{}
+		for _, hour := range hours {
+			times = append(times, fmt.Sprintf("%02d:%02d", hour, minutes[0]))
+		}
+		return "at " + joinDescriptions(times)
+	}
+	if len(minutes) == 1 && allHours {
+		if minutes[0] == 0 {
+			return "every hour"
+		}
+		return fmt.Sprintf("at %d minutes past every hour", minutes[0])
+	}
+	if interval, ok := regularInterval(hours, 24); ok && len(minutes) == 1 {
+		if minutes[0] == 0 {
+			return fmt.Sprintf("every %d hours", interval)
+		}
+		return fmt.Sprintf("at %d minutes past the hour, every %d hours", minutes[0], interval)
+	}
+	if interval, ok := regularInterval(minutes, 60); ok && allHours {
+		if interval == 1 {
+			return "every minute"
+		}
+		return fmt.Sprintf("every %d minutes", interval)
+	}
+	return fmt.Sprintf("at minute %s past hour %s", formatInts(minutes), formatInts(hours))
+}
+
+func (schedule *CronSchedule) describeDays() string {
+	domWildcard, dowWildcard := schedule.wildcards[2], schedule.wildcards[4]
+	days := []string{}
+	if !dowWildcard {
+		names := []string{}
+		for _, day := range schedule.daysOfWeek() {
+			names = append(names, time.Weekday(day).String())
+		}
+		days = append(days, "on "+joinDescriptions(names))
+	}
+	if !domWildcard {
+		days = append(days, "on day "+formatInts(schedule.daysOfMonth())+" of the month")
+	}
+	return strings.Join(days, " or ")
+}
+
+// regularInterval - returns the interval between the values if they're evenly spaced from zero over the given period
+func regularInterval(values []int, period int) (int, bool) {
+	if len(values) < 2 || values[0] != 0 {
+		return 0, false
+	}
+	interval := values[1] - values[0]
+	if period%interval != 0 || len(values) != period/interval {
+		return 0, false
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i]-values[i-1] != interval {
+			return 0, false
+		}
+	}
+	return interval, true
+}
+
+func formatInts(values []int) string {
+	strs := []string{}
+	for _, value := range values {
+		strs = append(strs, strconv.Itoa(value))
+	}
+	return strings.Join(strs, ",")
+}
+
+func joinDescriptions(items []string) string {
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
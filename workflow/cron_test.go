@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	scenarios := []struct {
+		expression          string
+		expectedError       string
+		expectedDescription string
+		expectedInterval    int
+	}{
+		{
+			expression:          "30 2 * * 1-5",
+			expectedDescription: "at 02:30, on Monday, Tuesday, Wednesday, Thursday and Friday (UTC)",
+			expectedInterval:    24 * 60,
+		},
+		{
+			expression:          "*/15 * * * *",
+			expectedDescription: "every 15 minutes (UTC)",
+			expectedInterval:    15,
+		},
+		{
+			expression:          "0 */6 1 JAN,JUL *",
+			expectedDescription: "every 6 hours, on day 1 of the month, in January and July (UTC)",
+			expectedInterval:    24 * 60,
+		},
+		{
+			expression:          "58,2 * * * SUN",
+			expectedDescription: "at minute 2,58 past hour 0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23, on Sunday (UTC)",
+			expectedInterval:    4,
+		},
+		{
+			expression:          "0 0 * * 5-7",
+			expectedDescription: "at 00:00, on Sunday, Friday and Saturday (UTC)",
+			expectedInterval:    24 * 60,
+		},
+		{
+			expression:          "0 0 * * 7",
+			expectedDescription: "at 00:00, on Sunday (UTC)",
+			expectedInterval:    24 * 60,
+		},
+		{
+			expression:          "0 0 * * */2",
+			expectedDescription: "at 00:00, on Sunday, Tuesday, Thursday and Saturday (UTC)",
+			expectedInterval:    24 * 60,
+		},
+		{
+			expression:    "0 25 * * *",
+			expectedError: `invalid hour field "25" (expected a number between 0 and 23)`,
+		},
+		{
+			expression:    "0 0 * *",
+			expectedError: "expected 5 fields but found 4",
+		},
+		{
+			expression:    "*/0 0 * * *",
+			expectedError: `invalid minute field "*/0" (invalid step)`,
+		},
+		{
+			expression:    "0 0 * FOO *",
+			expectedError: `invalid month field "FOO" (expected a number between 1 and 12)`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		schedule, err := ParseCron(scenario.expression)
+		if scenario.expectedError != "" {
+			assert.EqualError(t, err, scenario.expectedError, "Unexpected error for %q", scenario.expression)
+			continue
+		}
+		assert.NoError(t, err, "Unexpected error for %q", scenario.expression)
+		assert.Equal(t, scenario.expectedDescription, schedule.Describe(), "Unexpected description for %q", scenario.expression)
+		assert.Equal(t, scenario.expectedInterval, schedule.MinInterval(), "Unexpected interval for %q", scenario.expression)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	after := time.Date(2020, time.October, 9, 12, 0, 0, 0, time.UTC) // a Friday
+
+	schedule, _ := ParseCron("30 2 * * 1-5")
+	assert.Equal(t, []time.Time{
+		time.Date(2020, time.October, 12, 2, 30, 0, 0, time.UTC),
+		time.Date(2020, time.October, 13, 2, 30, 0, 0, time.UTC),
+	}, schedule.Next(after, 2))
+
+	schedule, _ = ParseCron("0 0 29 2 *")
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+	}, schedule.Next(after, 1))
+
+	// when both day fields are restricted, either may match
+	schedule, _ = ParseCron("0 0 13 * FRI")
+	assert.Equal(t, []time.Time{
+		time.Date(2020, time.October, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, time.October, 16, 0, 0, 0, 0, time.UTC),
+	}, schedule.Next(after, 2))
+}
@@ -1,14 +1,18 @@
 package workflow
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"strings"
 
 	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/thoas/go-funk"
 
 	"github.com/jbrunton/gflows/yamlutil"
 )
 
+const checksumPrefix = "# Checksum: "
+
 // Definition - definitoin for a workflow defined by a GFlows template
 type Definition struct {
 	Name        string
@@ -21,11 +25,7 @@ type Definition struct {
 }
 
 func (definition *Definition) SetContent(workflow string, template *pkg.PathInfo) {
-	meta := strings.Join([]string{
-		"# File generated by gflows, do not modify",
-		fmt.Sprintf("# Source: %s", template.Description),
-	}, "\n")
-	definition.Content = meta + "\n" + workflow
+	definition.Content = getHeader(template.Description, workflow) + "\n" + workflow
 	definition.Description = template.Description
 
 	json, err := yamlutil.YamlToJson(definition.Content)
@@ -38,3 +38,88 @@ func (definition *Definition) SetContent(workflow string, template *pkg.PathInfo
 		definition.JSON = json
 	}
 }
+
+// GeneratesSameWorkflow - returns true if the other definition generates the same workflow, ignoring formatting,
+// comments and headers
+func (definition *Definition) GeneratesSameWorkflow(other *Definition) bool {
+	return yamlutil.HaveSameValues(getWorkflowBody(definition.Content), getWorkflowBody(other.Content))
+}
+
+// GetJobs - returns the jobs in the generated workflow, keyed by job name
+func (definition *Definition) GetJobs() map[string]interface{} {
+	return getJobs(definition.JSON)
+}
+
+// CheckDestinations - marks any definitions which would be written to the same destination as invalid, since
+// otherwise they'd overwrite each other
+func CheckDestinations(definitions []*Definition) {
+	sources := make(map[string][]string)
+	for _, definition := range definitions {
+		if definition.Status.Valid && !funk.ContainsString(sources[definition.Destination], definition.Source) {
+			sources[definition.Destination] = append(sources[definition.Destination], definition.Source)
+		}
+	}
+	counts := make(map[string]int)
+	for _, definition := range definitions {
+		if definition.Status.Valid {
+			counts[definition.Destination]++
+		}
+	}
+	for _, definition := range definitions {
+		if !definition.Status.Valid || counts[definition.Destination] < 2 {
+			continue
+		}
+		definitionSources := sources[definition.Destination]
+		message := fmt.Sprintf("%s is generated more than once by %s", definition.Destination, definitionSources[0])
+		if len(definitionSources) > 1 {
+			message = fmt.Sprintf("%s is generated by more than one template (%s)", definition.Destination, strings.Join(definitionSources, ", "))
+		}
+		definition.Status = ValidationResult{Valid: false, Errors: []string{message}}
+	}
+}
+
+// Checksum - returns the checksum embedded in the header of generated workflows
+func Checksum(workflow string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(workflow)))
+}
+
+func getHeader(description string, workflow string) string {
+	return strings.Join([]string{
+		"# File generated by gflows, do not modify",
+		fmt.Sprintf("# Source: %s", description),
+		checksumPrefix + Checksum(workflow),
+	}, "\n")
+}
+
+// getWorkflowBody - returns the content of a workflow after the gflows header, or all of it if there's no header
+func getWorkflowBody(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#") {
+			// end of the header
+			break
+		}
+		if strings.HasPrefix(line, checksumPrefix) {
+			return strings.Join(lines[i+1:], "")
+		}
+	}
+	return content
+}
+
+// IsHandEdited - returns true if the content of a generated workflow no longer matches the checksum in its header,
+// i.e. if it's been modified since it was generated. Returns false for workflows without a checksum.
+func IsHandEdited(content string) bool {
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#") {
+			// end of the header
+			return false
+		}
+		if strings.HasPrefix(line, checksumPrefix) {
+			checksum := strings.TrimSpace(strings.TrimPrefix(line, checksumPrefix))
+			workflow := strings.Join(lines[i+1:], "")
+			return checksum != Checksum(workflow)
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDefinition(workflow string) *Definition {
+	definition := &Definition{Status: ValidationResult{Valid: true}}
+	definition.SetContent(workflow, &pkg.PathInfo{Description: "workflows/test.jsonnet"})
+	return definition
+}
+
+func TestGeneratesSameWorkflow(t *testing.T) {
+	definition := newTestDefinition("# Runs the tests\non: push\n")
+	otherDefinition := &Definition{Status: ValidationResult{Valid: true}}
+	otherDefinition.SetContent("\"on\": push\n", &pkg.PathInfo{Description: "workflows/test/test.yml"})
+
+	assert.True(t, definition.GeneratesSameWorkflow(otherDefinition))
+	assert.False(t, definition.GeneratesSameWorkflow(newTestDefinition("on: pull_request\n")))
+}
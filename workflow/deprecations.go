@@ -0,0 +1,125 @@
+package workflow
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	statikFs "github.com/rakyll/statik/fs"
+	"github.com/thoas/go-funk"
+	"gopkg.in/yaml.v2"
+)
+
+// Deprecations - deprecated workflow features, loaded from the embedded deprecations.yml file
+type Deprecations struct {
+	Commands []struct {
+		Name        string
+		Alternative string
+	}
+	Runners []struct {
+		Label       string
+		Alternative string
+	}
+	Actions []struct {
+		Name        string
+		Versions    []string
+		Alternative string
+	}
+}
+
+// LoadDeprecations - loads the embedded list of deprecated workflow commands, runner labels and action versions
+func LoadDeprecations() *Deprecations {
+	sourceFs, err := statikFs.New()
+	if err != nil {
+		panic(err)
+	}
+	file, err := sourceFs.Open("/deprecations.yml")
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		panic(err)
+	}
+	deprecations := &Deprecations{}
+	err = yaml.Unmarshal(data, deprecations)
+	if err != nil {
+		panic(err)
+	}
+	return deprecations
+}
+
+// checkDeprecations - returns a message for every use of a deprecated workflow command, runner label or action
+// version
+func (deprecations *Deprecations) checkDeprecations(workflowJSON interface{}) []string {
+	messages := []string{}
+	jobs := getJobs(workflowJSON)
+	for _, jobName := range sortedKeys(jobs) {
+		job, _ := jobs[jobName].(map[string]interface{})
+		path := fmt.Sprintf("jobs.%s", jobName)
+
+		for _, label := range getRunnerLabels(job) {
+			for _, runner := range deprecations.Runners {
+				if label == runner.Label {
+					messages = append(messages, fmt.Sprintf("%s.runs-on: the %s runner has been retired, %s", path, label, runner.Alternative))
+				}
+			}
+		}
+
+		for i, step := range getSteps(job) {
+			stepMap, _ := step.(map[string]interface{})
+			stepPath := fmt.Sprintf("%s.steps[%d]", path, i)
+			if script, ok := stepMap["run"].(string); ok {
+				for _, command := range deprecations.Commands {
+					if strings.Contains(script, "::"+command.Name+" ") || strings.Contains(script, "::"+command.Name+"::") {
+						messages = append(messages, fmt.Sprintf("%s.run: the %s command is deprecated, %s", stepPath, command.Name, command.Alternative))
+					}
+				}
+			}
+			if uses, ok := stepMap["uses"].(string); ok {
+				if message := deprecations.checkActionVersion(uses); message != "" {
+					messages = append(messages, fmt.Sprintf("%s.uses: %s", stepPath, message))
+				}
+			}
+		}
+	}
+	return messages
+}
+
+func (deprecations *Deprecations) checkActionVersion(uses string) string {
+	parts := strings.SplitN(uses, "@", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	name, ref := parts[0], parts[1]
+	for _, action := range deprecations.Actions {
+		if name != action.Name {
+			continue
+		}
+		for _, version := range action.Versions {
+			// match both major versions (e.g. v2) and releases (e.g. v2.3.4)
+			if ref == version || strings.HasPrefix(ref, version+".") {
+				return fmt.Sprintf("%s runs on a deprecated version of Node, %s", uses, action.Alternative)
+			}
+		}
+	}
+	return ""
+}
+
+// getRunnerLabels - returns the runner labels for a job, ignoring any groups or expressions
+func getRunnerLabels(job map[string]interface{}) []string {
+	switch runsOn := job["runs-on"].(type) {
+	case string:
+		return []string{runsOn}
+	case []interface{}:
+		labels := []string{}
+		for _, label := range runsOn {
+			if label, ok := label.(string); ok && !funk.ContainsString(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		return labels
+	}
+	return []string{}
+}
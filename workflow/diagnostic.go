@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic - a structured template error. The location fields are only given if the engine reports them.
+type Diagnostic struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	// Snippet - the line of source code at the location of the error
+	Snippet string `json:"snippet,omitempty"`
+	// Length - the length of the failing expression within Snippet, starting at Column
+	Length int `json:"length,omitempty"`
+	// Stack - the locations of the calls which led to the error, innermost first
+	Stack []string `json:"stack,omitempty"`
+}
+
+// String - formats the diagnostic with its location and, if known, a snippet of the source code with a caret under
+// the failing expression
+func (diagnostic Diagnostic) String() string {
+	lines := []string{diagnostic.Message}
+	if location := diagnostic.Location(); location != "" {
+		lines[0] = fmt.Sprintf("%s: %s", location, diagnostic.Message)
+	}
+
+	if diagnostic.Snippet != "" {
+		snippet := strings.ReplaceAll(diagnostic.Snippet, "\t", " ")
+		lineNumber := fmt.Sprintf("%d", diagnostic.Line)
+		gutter := strings.Repeat(" ", len(lineNumber))
+		column, length := diagnostic.getCaretRange()
+		lines = append(lines,
+			fmt.Sprintf("%s |", gutter),
+			fmt.Sprintf("%s | %s", lineNumber, snippet),
+			fmt.Sprintf("%s | %s%s", gutter, strings.Repeat(" ", column-1), strings.Repeat("^", length)))
+	}
+
+	for _, frame := range diagnostic.Stack {
+		lines = append(lines, "at "+frame)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Location - returns the location of the error, formatted as file:line:column
+func (diagnostic Diagnostic) Location() string {
+	location := diagnostic.File
+	if diagnostic.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, diagnostic.Line)
+		if diagnostic.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, diagnostic.Column)
+		}
+	}
+	return location
+}
+
+// getCaretRange - returns the column and length of the failing expression in the snippet. If the column isn't known
+// then this is the template expression (for templates with #@ annotations), or else the whole line.
+func (diagnostic Diagnostic) getCaretRange() (int, int) {
+	snippet := strings.TrimRight(diagnostic.Snippet, " \t\r\n")
+	if diagnostic.Column > 0 {
+		column := diagnostic.Column
+		if column > len(snippet) {
+			return len(snippet) + 1, 1
+		}
+		length := diagnostic.Length
+		if length <= 0 || column+length-1 > len(snippet) {
+			length = len(snippet) - column + 1
+		}
+		return column, length
+	}
+	start := len(snippet) - len(strings.TrimLeft(snippet, " \t"))
+	if index := strings.Index(snippet, "#@"); index >= 0 {
+		start = index + len("#@")
+		start += len(snippet[start:]) - len(strings.TrimLeft(snippet[start:], " "))
+	}
+	if start >= len(snippet) {
+		return start + 1, 1
+	}
+	return start + 1, len(snippet) - start
+}
+
+// FormatDiagnostics - returns the formatted diagnostics for the result, or else its errors
+func (result ValidationResult) FormatDiagnostics() []string {
+	if len(result.Diagnostics) == 0 {
+		return result.Errors
+	}
+	formatted := []string{}
+	for _, diagnostic := range result.Diagnostics {
+		formatted = append(formatted, diagnostic.String())
+	}
+	return formatted
+}
+
+// GetDiagnostics - returns the diagnostics for an invalid template. If the engine didn't report any then there's a
+// diagnostic for each error, located at the template source.
+func (definition *Definition) GetDiagnostics() []Diagnostic {
+	if definition.Status.Valid {
+		return nil
+	}
+	if len(definition.Status.Diagnostics) > 0 {
+		return definition.Status.Diagnostics
+	}
+	diagnostics := []Diagnostic{}
+	for _, err := range definition.Status.Errors {
+		diagnostics = append(diagnostics, Diagnostic{File: definition.Source, Message: err})
+	}
+	return diagnostics
+}
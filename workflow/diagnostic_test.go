@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDiagnostic(t *testing.T) {
+	scenarios := []struct {
+		description    string
+		diagnostic     Diagnostic
+		expectedOutput []string
+	}{
+		{
+			description:    "message only",
+			diagnostic:     Diagnostic{Message: "something went wrong"},
+			expectedOutput: []string{"something went wrong"},
+		},
+		{
+			description: "column and length",
+			diagnostic: Diagnostic{
+				File:    "test.jsonnet",
+				Line:    3,
+				Column:  9,
+				Length:  5,
+				Message: "unknown variable",
+				Snippet: "  jobs: error,",
+				Stack:   []string{"test.jsonnet:3:9-14 object <anonymous>"},
+			},
+			expectedOutput: []string{
+				"test.jsonnet:3:9: unknown variable",
+				"  |",
+				"3 |   jobs: error,",
+				"  |         ^^^^^",
+				"at test.jsonnet:3:9-14 object <anonymous>",
+			},
+		},
+		{
+			description: "template expression",
+			diagnostic: Diagnostic{
+				File:    "test/config.yml",
+				Line:    12,
+				Message: "undefined: foo",
+				Snippet: "    runs-on: #@ foo.bar",
+			},
+			expectedOutput: []string{
+				"test/config.yml:12: undefined: foo",
+				"   |",
+				"12 |     runs-on: #@ foo.bar",
+				"   |                 ^^^^^^^",
+			},
+		},
+		{
+			description: "whole line",
+			diagnostic: Diagnostic{
+				File:    "test/config.yml",
+				Line:    2,
+				Message: "did not find expected key",
+				Snippet: "  foo: [bar",
+			},
+			expectedOutput: []string{
+				"test/config.yml:2: did not find expected key",
+				"  |",
+				"2 |   foo: [bar",
+				"  |   ^^^^^^^^^",
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.description, func(t *testing.T) {
+			assert.Equal(t, strings.Join(scenario.expectedOutput, "\n"), scenario.diagnostic.String())
+		})
+	}
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	definition := Definition{
+		Source: "test.jsonnet",
+		Status: ValidationResult{Valid: false, Errors: []string{"error"}},
+	}
+	assert.Equal(t, []Diagnostic{{File: "test.jsonnet", Message: "error"}}, definition.GetDiagnostics())
+	assert.Equal(t, []string{"error"}, definition.Status.FormatDiagnostics())
+
+	definition.Status.Diagnostics = []Diagnostic{{File: "test.jsonnet", Line: 1, Message: "error"}}
+	assert.Equal(t, definition.Status.Diagnostics, definition.GetDiagnostics())
+	assert.Equal(t, []string{"test.jsonnet:1: error"}, definition.Status.FormatDiagnostics())
+
+	definition.Status.Valid = true
+	assert.Nil(t, definition.GetDiagnostics())
+}
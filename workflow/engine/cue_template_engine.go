@@ -0,0 +1,325 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	cueerrors "cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	cueyaml "cuelang.org/go/encoding/yaml"
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/spf13/afero"
+)
+
+// cuePackageName - the package name used for imported and generated templates
+const cuePackageName = "workflows"
+
+type CueTemplateEngine struct {
+	fs            *afero.Afero
+	context       *config.GFlowsContext
+	contentWriter *content.Writer
+	env           *env.GFlowsEnv
+}
+
+func NewCueTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv) *CueTemplateEngine {
+	return &CueTemplateEngine{
+		fs:            fs,
+		context:       context,
+		contentWriter: contentWriter,
+		env:           env,
+	}
+}
+
+func (engine *CueTemplateEngine) GetObservableSources() ([]string, error) {
+	files := []string{}
+	for _, libPath := range append(
+		engine.context.Config.GetAllLibs(),
+		engine.context.WorkflowsDir(),
+		engine.context.LibsDir(),
+	) {
+		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if libInfo.IsRemote || !libInfo.Exists {
+			// Can't watch remote or non-existent files, so continue
+			continue
+		}
+
+		if !libInfo.IsDir {
+			files = append(files, libPath)
+			continue
+		}
+
+		sources, err := engine.getSourcesInDir(libPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sources...)
+	}
+
+	return files, nil
+}
+
+// GetWorkflowDefinitions - get workflow definitions for the given context
+func (engine *CueTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	definitions := []*workflow.Definition{}
+	for _, template := range templates {
+		workflowName := engine.getWorkflowName(template.LocalPath)
+		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+		definition := &workflow.Definition{
+			Name:        workflowName,
+			Source:      template.LocalPath,
+			Destination: destinationPath,
+			Status:      workflow.ValidationResult{Valid: true},
+		}
+
+		workflow, err := engine.apply(workflowName, template.LocalPath)
+
+		if err != nil {
+			definition.Status.Valid = false
+			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+		} else {
+			definition.SetContent(workflow, template)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+func (engine *CueTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return "", err
+	}
+
+	normalizedContent, err := yamlutil.NormalizeWorkflow(workflowContent)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := cueyaml.Extract(wf.Path, normalizedContent)
+	if err != nil {
+		return "", err
+	}
+	file.Decls = append([]ast.Decl{&ast.Package{Name: ast.NewIdent(cuePackageName)}}, file.Decls...)
+	templateContent, err := format.Node(file, format.Simplify())
+	if err != nil {
+		return "", err
+	}
+
+	templatePath := engine.GetImportedTemplatePath(wf)
+	engine.contentWriter.SafelyWriteFile(templatePath, string(templateContent))
+
+	return templatePath, nil
+}
+
+func (engine *CueTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
+	_, filename := filepath.Split(wf.Path)
+	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return filepath.Join(engine.context.WorkflowsDir(), templateName+".cue")
+}
+
+func (engine *CueTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
+	return content.WorkflowGenerator{
+		Name:         "gflows",
+		TemplateVars: templateVars,
+		Sources: []content.WorkflowSource{
+			content.NewWorkflowSource("/cue/libs/steps.cue", "/libs/steps.cue"),
+			content.NewWorkflowSource("/cue/libs/workflows.cue", "/libs/workflows.cue"),
+			content.NewWorkflowSource("/cue/workflows/gflows.cue", "/workflows/$WORKFLOW_NAME.cue"),
+			content.NewWorkflowSource("/cue/config.yml", "/config.yml"),
+		},
+	}
+}
+
+func (engine *CueTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error) {
+	templates := []*pkg.PathInfo{}
+	packages, err := engine.env.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		paths, err := afero.Glob(engine.fs, filepath.Join(pkg.WorkflowsDir(), "*.cue"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			pathInfo, err := pkg.GetPathInfo(path)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, pathInfo)
+		}
+	}
+	return templates, nil
+}
+
+func (engine *CueTemplateEngine) getWorkflowName(filename string) string {
+	_, templateFileName := filepath.Split(filename)
+	return strings.TrimSuffix(templateFileName, filepath.Ext(templateFileName))
+}
+
+func (engine *CueTemplateEngine) getSourcesInDir(dir string) ([]string, error) {
+	files := []string{}
+	err := engine.fs.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !f.IsDir() && filepath.Ext(path) == ".cue" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// apply - evaluates the template together with any files in the lib paths which belong to the same CUE package,
+// and returns the result as YAML. Imports are resolved to the lib files in the imported package (e.g. import "steps"
+// or import "example.com/steps" loads the lib files with "package steps").
+func (engine *CueTemplateEngine) apply(workflowName string, templatePath string) (string, error) {
+	source, err := engine.fs.ReadFile(templatePath)
+	if err != nil {
+		return "", err
+	}
+	packageName, err := engine.getPackageName(templatePath, source)
+	if err != nil {
+		return "", err
+	}
+
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return "", err
+	}
+	libFiles, err := engine.getLibFilesByPackage(libPaths)
+	if err != nil {
+		return "", err
+	}
+
+	var loadImport build.LoadFunc
+	context := build.NewContext()
+	newInstance := func(dir string, files []*cueLibFile) *build.Instance {
+		instance := context.NewInstance(dir, loadImport)
+		for _, file := range files {
+			if err := instance.AddFile(file.path, file.source); err != nil {
+				instance.Err = cueerrors.Promote(err, "")
+			}
+		}
+		return instance
+	}
+	loadImport = func(pos token.Pos, path string) *build.Instance {
+		importedFiles := libFiles[getCueImportPackageName(path)]
+		if len(importedFiles) == 0 {
+			// not a lib package, so leave CUE to resolve it (e.g. as a builtin package)
+			return nil
+		}
+		return newInstance(filepath.Dir(importedFiles[0].path), importedFiles)
+	}
+
+	instance := newInstance(filepath.Dir(templatePath), append([]*cueLibFile{{path: templatePath, source: source}}, libFiles[packageName]...))
+	if instance.Err != nil {
+		return "", formatCueError(instance.Err)
+	}
+
+	var runtime cue.Runtime
+	result, err := runtime.Build(instance)
+	if err != nil {
+		return "", formatCueError(err)
+	}
+	value := result.Value()
+	if err := value.Validate(cue.Concrete(true)); err != nil {
+		return "", formatCueError(err)
+	}
+	output, err := cueyaml.Encode(value)
+	if err != nil {
+		return "", formatCueError(err)
+	}
+
+	// The YAML encoder doesn't quote the "on" key
+	return yamlutil.NormalizeWorkflow(string(output))
+}
+
+type cueLibFile struct {
+	path   string
+	source []byte
+}
+
+// getLibFilesByPackage - returns the CUE files in the lib paths, keyed by package name
+func (engine *CueTemplateEngine) getLibFilesByPackage(libPaths []string) (map[string][]*cueLibFile, error) {
+	libFiles := make(map[string][]*cueLibFile)
+	for _, libPath := range libPaths {
+		paths, err := engine.getLibFiles(libPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			source, err := engine.fs.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			packageName, err := engine.getPackageName(path, source)
+			if err != nil {
+				return nil, err
+			}
+			libFiles[packageName] = append(libFiles[packageName], &cueLibFile{path: path, source: source})
+		}
+	}
+	return libFiles, nil
+}
+
+// getCueImportPackageName - returns the package name for an import path, which is given by its qualifier if it has
+// one (e.g. "example.com/steps:lib"), or otherwise its last element
+func getCueImportPackageName(path string) string {
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		return path[i+1:]
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func (engine *CueTemplateEngine) getLibFiles(libPath string) ([]string, error) {
+	isDir, err := engine.fs.IsDir(libPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	if !isDir {
+		if filepath.Ext(libPath) == ".cue" {
+			return []string{libPath}, nil
+		}
+		return []string{}, nil
+	}
+	return engine.getSourcesInDir(libPath)
+}
+
+func (engine *CueTemplateEngine) getPackageName(path string, source []byte) (string, error) {
+	file, err := parser.ParseFile(path, source, parser.PackageClauseOnly)
+	if err != nil {
+		return "", formatCueError(err)
+	}
+	return file.PackageName(), nil
+}
+
+func formatCueError(err error) error {
+	return fmt.Errorf("%s", strings.TrimSpace(cueerrors.Details(err, nil)))
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleCueTemplate = `package workflows
+
+name: "test"
+on: push: branches: ["develop"]
+jobs: test: {
+	"runs-on": "ubuntu-latest"
+	steps: [#Steps.hello]
+}
+`
+
+const exampleCueLib = `package workflows
+
+#Steps: hello: run: "echo hello, world!"
+`
+
+const exampleCueWorkflow = `name: test
+"on":
+  push:
+    branches:
+    - develop
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo hello, world!
+`
+
+func newCueTemplateEngine(config string, roundTripper http.RoundTripper) (*content.Container, *config.GFlowsContext, *CueTemplateEngine) {
+	if config == "" {
+		config = "templates:\n  engine: cue"
+	}
+	ioContainer, context, _ := fixtures.NewTestContext(config)
+	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
+	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
+	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
+	templateEngine := NewCueTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env)
+	return container, context, templateEngine
+}
+
+func TestGetCueWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte(exampleCueTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.cue", []byte(exampleCueLib), 0644)
+	fs.WriteFile(".gflows/libs/other.cue", []byte("package other\n\nfoo: \"bar\"\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, "test", definitions[0].Name)
+	assert.Equal(t, ".github/workflows/test.yml", definitions[0].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.cue", exampleCueWorkflow), definitions[0].Content)
+}
+
+func TestGetCueWorkflowDefinitionsWithImports(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte(strings.Join([]string{
+		`package workflows`,
+		``,
+		`import (`,
+		`	"strings"`,
+		`	"example.com/common"`,
+		`)`,
+		``,
+		`name: strings.ToLower("TEST")`,
+		`on: push: branches: ["develop"]`,
+		`jobs: test: {`,
+		`	"runs-on": "ubuntu-latest"`,
+		`	steps: [common.#Hello]`,
+		`}`,
+	}, "\n")), 0644)
+	fs.WriteFile(".gflows/libs/common/steps.cue", []byte("package common\n\n#Hello: run: \"echo hello, world!\"\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.cue", exampleCueWorkflow), definitions[0].Content)
+}
+
+func TestGetCueWorkflowDefinitionsWithUnknownImports(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte("package workflows\n\nimport \"example.com/steps\"\n\nname: steps.#Name\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{Valid: false, Errors: []string{"package \"example.com/steps\" not found:\n    .gflows/workflows/test.cue:3:8"}}, definitions[0].Status)
+}
+
+func TestGetCueWorkflowDefinitionsWithPackages(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: cue",
+		"  defaults:",
+		"    dependencies:",
+		"    - /path/to/my-lib",
+	}, "\n")
+	container, _, templateEngine := newCueTemplateEngine(config, fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte(exampleCueTemplate), 0644)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/steps.cue", "workflows/lib-workflow.cue"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/steps.cue", exampleCueLib)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/lib-workflow.cue", "package workflows\n\nname: \"lib\"\n")
+	lib, _ := templateEngine.env.LoadDependency("/path/to/my-lib")
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, filepath.Join(lib.LocalDir, "workflows/lib-workflow.cue"), definitions[0].Source)
+	assert.Equal(t, fixtures.GeneratedWorkflow("my-lib/workflows/lib-workflow.cue", "name: lib\n"), definitions[0].Content)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.cue", exampleCueWorkflow), definitions[1].Content)
+}
+
+func TestGetCueWorkflowDefinitionsWithErrors(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte("package workflows\n\nname: string\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{"name: incomplete value (string):\n    .gflows/workflows/test.cue:3:7"},
+	}, definitions[0].Status)
+}
+
+func TestGetCueObservableSources(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.cue", []byte(exampleCueTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/invalid.ext", []byte(exampleCueTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.cue", []byte(exampleCueLib), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		".gflows/workflows/test.cue",
+		".gflows/libs/steps.cue",
+	}, sources)
+}
+
+func TestImportCueWorkflow(t *testing.T) {
+	container, _, templateEngine := newCueTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".github/workflows/test.yml", []byte(exampleCueWorkflow), 0644)
+
+	templatePath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/test.yml"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/test.cue", templatePath)
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.cue", exampleCueWorkflow), definitions[0].Content)
+}
@@ -0,0 +1,91 @@
+package gotemplate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EscapeExpressions - escapes any GitHub ${{ }} expressions in the template source, so that they're passed through
+// to the generated workflow unchanged
+func EscapeExpressions(source string) string {
+	return strings.ReplaceAll(source, "${{", `${{"{{"}}`)
+}
+
+// FuncMap - returns the helper functions available to templates. The include function renders templates defined in
+// the given template set.
+func FuncMap(tmpl *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var buf bytes.Buffer
+			err := tmpl.ExecuteTemplate(&buf, name, data)
+			return buf.String(), err
+		},
+		"toYaml":  toYaml,
+		"indent":  indent,
+		"nindent": func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"quote":   func(value interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(value)) },
+		"default": func(defaultValue interface{}, value interface{}) interface{} {
+			if value == nil || value == "" {
+				return defaultValue
+			}
+			return value
+		},
+		"list": func(values ...interface{}) []interface{} { return values },
+		"dict": dict,
+		"join": func(sep string, values []interface{}) string {
+			strs := []string{}
+			for _, value := range values {
+				strs = append(strs, fmt.Sprint(value))
+			}
+			return strings.Join(strs, sep)
+		},
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"replace":   func(old string, new string, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":  func(substr string, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": func(prefix string, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix": func(suffix string, s string) bool { return strings.HasSuffix(s, suffix) },
+	}
+}
+
+func toYaml(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func indent(spaces int, s string) string {
+	padding := strings.Repeat(" ", spaces)
+	return padding + strings.ReplaceAll(s, "\n", "\n"+padding)
+}
+
+func dict(values ...interface{}) (map[string]interface{}, error) {
+	if len(values)%2 != 0 {
+		return nil, fmt.Errorf("dict expects an even number of arguments, got %d", len(values))
+	}
+	result := make(map[string]interface{})
+	for i := 0; i < len(values); i += 2 {
+		key, ok := values[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict keys must be strings, got %v", values[i])
+		}
+		result[key] = values[i+1]
+	}
+	return result, nil
+}
+
+var delimiterRegex = regexp.MustCompile(`(^|[^$]){{`)
+
+// EscapeDelimiters - escapes any template delimiters in plain YAML content (other than those in GitHub expressions,
+// which EscapeExpressions handles), so that the content renders to itself
+func EscapeDelimiters(source string) string {
+	return delimiterRegex.ReplaceAllString(source, `${1}{{"{{"}}`)
+}
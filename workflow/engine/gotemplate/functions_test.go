@@ -0,0 +1,42 @@
+package gotemplate
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(t *testing.T, source string, data interface{}) string {
+	tmpl := template.New("test")
+	tmpl, err := tmpl.Funcs(FuncMap(tmpl)).Parse(EscapeExpressions(source))
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.Execute(&buf, data))
+	return buf.String()
+}
+
+func TestEscapeExpressions(t *testing.T) {
+	assert.Equal(t, "run: echo ${{ github.sha }} hello", render(t, "run: echo ${{ github.sha }} {{ .name }}", map[string]string{"name": "hello"}))
+	assert.Equal(t, "${{ matrix.os }}", render(t, "${{ matrix.{{ .key }} }}", map[string]string{"key": "os"}))
+}
+
+func TestFunctions(t *testing.T) {
+	data := map[string]interface{}{
+		"branches": []interface{}{"main", "develop"},
+		"env":      map[string]interface{}{"FOO": "bar"},
+	}
+	assert.Equal(t, "branches:\n  - main\n  - develop", render(t, "branches:{{ toYaml .branches | nindent 2 }}", data))
+	assert.Equal(t, `"main,develop"`, render(t, `{{ join "," .branches | quote }}`, data))
+	assert.Equal(t, "fallback", render(t, `{{ default "fallback" .missing }}`, data))
+	assert.Equal(t, "HELLO", render(t, `{{ define "greeting" }}hello{{ end }}{{ include "greeting" . | upper }}`, data))
+	assert.Equal(t, "name: world", render(t, `{{ define "name" }}name: {{ .name }}{{ end }}{{ include "name" (dict "name" "world") }}`, data))
+}
+
+func TestEscapeDelimiters(t *testing.T) {
+	source := "run: docker ps --format '{{.ID}}' && echo ${{ github.sha }}"
+	escaped := EscapeDelimiters(source)
+	assert.Equal(t, `run: docker ps --format '{{"{{"}}.ID}}' && echo ${{ github.sha }}`, escaped)
+	assert.Equal(t, source, render(t, escaped, nil))
+}
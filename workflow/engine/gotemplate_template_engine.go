@@ -0,0 +1,293 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/workflow/engine/gotemplate"
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/spf13/afero"
+)
+
+// goTemplateExt - the extension for workflow templates
+const goTemplateExt = ".yml.tmpl"
+
+// goTemplateValuesFile - the name of the file in lib directories which provides values to templates
+const goTemplateValuesFile = "values.yml"
+
+type GoTemplateEngine struct {
+	fs            *afero.Afero
+	context       *config.GFlowsContext
+	contentWriter *content.Writer
+	env           *env.GFlowsEnv
+}
+
+// goTemplateData - the data passed to workflow templates
+type goTemplateData struct {
+	Workflow string
+	Values   map[string]interface{}
+}
+
+func NewGoTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv) *GoTemplateEngine {
+	return &GoTemplateEngine{
+		fs:            fs,
+		context:       context,
+		contentWriter: contentWriter,
+		env:           env,
+	}
+}
+
+func (engine *GoTemplateEngine) GetObservableSources() ([]string, error) {
+	files := []string{}
+	for _, libPath := range append(
+		engine.context.Config.GetAllLibs(),
+		engine.context.WorkflowsDir(),
+		engine.context.LibsDir(),
+	) {
+		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if libInfo.IsRemote || !libInfo.Exists {
+			// Can't watch remote or non-existent files, so continue
+			continue
+		}
+
+		if !libInfo.IsDir {
+			files = append(files, libPath)
+			continue
+		}
+
+		sources, err := engine.getSourcesInDir(libPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sources...)
+	}
+
+	return files, nil
+}
+
+// GetWorkflowDefinitions - get workflow definitions for the given context
+func (engine *GoTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	definitions := []*workflow.Definition{}
+	for _, template := range templates {
+		workflowName := engine.getWorkflowName(template.LocalPath)
+		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+		definition := &workflow.Definition{
+			Name:        workflowName,
+			Source:      template.LocalPath,
+			Destination: destinationPath,
+			Status:      workflow.ValidationResult{Valid: true},
+		}
+
+		workflow, err := engine.apply(workflowName, template.LocalPath)
+
+		if err != nil {
+			definition.Status.Valid = false
+			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+		} else {
+			definition.SetContent(workflow, template)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+func (engine *GoTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return "", err
+	}
+
+	normalizedContent, err := yamlutil.NormalizeWorkflow(workflowContent)
+	if err != nil {
+		return "", err
+	}
+	templateContent := gotemplate.EscapeDelimiters(normalizedContent)
+
+	templatePath := engine.GetImportedTemplatePath(wf)
+	engine.contentWriter.SafelyWriteFile(templatePath, templateContent)
+
+	return templatePath, nil
+}
+
+func (engine *GoTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
+	_, filename := filepath.Split(wf.Path)
+	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return filepath.Join(engine.context.WorkflowsDir(), templateName+goTemplateExt)
+}
+
+func (engine *GoTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
+	return content.WorkflowGenerator{
+		Name:         "gflows",
+		TemplateVars: templateVars,
+		Sources: []content.WorkflowSource{
+			content.NewWorkflowSource("/gotemplate/libs/steps.tmpl", "/libs/steps.tmpl"),
+			content.NewWorkflowSource("/gotemplate/libs/workflows.tmpl", "/libs/workflows.tmpl"),
+			content.NewWorkflowSource("/gotemplate/libs/values.yml", "/libs/values.yml"),
+			content.NewWorkflowSource("/gotemplate/workflows/gflows.yml.tmpl", "/workflows/$WORKFLOW_NAME.yml.tmpl"),
+			content.NewWorkflowSource("/gotemplate/config.yml", "/config.yml"),
+		},
+	}
+}
+
+func (engine *GoTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error) {
+	templates := []*pkg.PathInfo{}
+	packages, err := engine.env.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		paths, err := afero.Glob(engine.fs, filepath.Join(pkg.WorkflowsDir(), "*"+goTemplateExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			pathInfo, err := pkg.GetPathInfo(path)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, pathInfo)
+		}
+	}
+	return templates, nil
+}
+
+func (engine *GoTemplateEngine) getWorkflowName(filename string) string {
+	_, templateFileName := filepath.Split(filename)
+	return strings.TrimSuffix(templateFileName, goTemplateExt)
+}
+
+func (engine *GoTemplateEngine) getSourcesInDir(dir string) ([]string, error) {
+	files := []string{}
+	err := engine.fs.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !f.IsDir() && (filepath.Ext(path) == ".tmpl" || filepath.Base(path) == goTemplateValuesFile) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// apply - renders the template, with any partials and values found in the lib paths
+func (engine *GoTemplateEngine) apply(workflowName string, templatePath string) (string, error) {
+	tmpl := template.New(filepath.Base(templatePath))
+	tmpl.Funcs(gotemplate.FuncMap(tmpl))
+
+	data := goTemplateData{
+		Workflow: workflowName,
+		Values:   map[string]interface{}{},
+	}
+
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return "", err
+	}
+	partials := make(map[string]string)
+	for _, libPath := range libPaths {
+		libFiles, err := engine.getLibFiles(libPath)
+		if err != nil {
+			return "", err
+		}
+		for _, libFile := range libFiles {
+			if filepath.Base(libFile) == goTemplateValuesFile {
+				err = engine.loadValues(libFile, data.Values)
+			} else {
+				name := getPartialName(libPath, libFile)
+				if existing, ok := partials[name]; ok {
+					return "", fmt.Errorf("partial %q is defined by both %s and %s", name, existing, libFile)
+				}
+				partials[name] = libFile
+				err = engine.parse(tmpl.New(name), libFile)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Parse the workflow template last, so that it takes precedence over any partials with the same name
+	if err := engine.parse(tmpl, templatePath); err != nil {
+		return "", err
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", err
+	}
+
+	return yamlutil.NormalizeWorkflow(output.String())
+}
+
+// getPartialName - returns the name of a partial, which is its path relative to the lib path it was found in
+func getPartialName(libPath string, libFile string) string {
+	name, err := filepath.Rel(libPath, libFile)
+	if err != nil || name == "." {
+		return filepath.Base(libFile)
+	}
+	return filepath.ToSlash(name)
+}
+
+func (engine *GoTemplateEngine) parse(tmpl *template.Template, path string) error {
+	source, err := engine.fs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = tmpl.Parse(gotemplate.EscapeExpressions(string(source)))
+	return err
+}
+
+// loadValues - merges the top level keys in the given values file into values
+func (engine *GoTemplateEngine) loadValues(path string, values map[string]interface{}) error {
+	source, err := engine.fs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	json, err := yamlutil.YamlToJson(string(source))
+	if err != nil {
+		return err
+	}
+	if json, ok := json.(map[string]interface{}); ok {
+		for key, value := range json {
+			values[key] = value
+		}
+	}
+	return nil
+}
+
+func (engine *GoTemplateEngine) getLibFiles(libPath string) ([]string, error) {
+	isDir, err := engine.fs.IsDir(libPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	if !isDir {
+		if filepath.Ext(libPath) == ".tmpl" || filepath.Base(libPath) == goTemplateValuesFile {
+			return []string{libPath}, nil
+		}
+		return []string{}, nil
+	}
+	return engine.getSourcesInDir(libPath)
+}
@@ -0,0 +1,187 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleGoTemplate = `name: {{ .Workflow }}
+on:
+  push:
+    branches: [{{ .Values.main_branch }}]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    {{- range list "hello" "goodbye" }}
+    {{- include "echo" . | nindent 4 }}
+    {{- end }}
+    - run: echo ${{ github.sha }}
+`
+
+const exampleGoTemplateLib = `{{ define "echo" }}- run: echo {{ . }}, world!{{ end }}`
+
+const exampleGoTemplateWorkflow = `name: test
+"on":
+  push:
+    branches:
+    - develop
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+    - run: echo hello, world!
+    - run: echo goodbye, world!
+    - run: echo ${{ github.sha }}
+`
+
+func newGoTemplateEngine(config string, roundTripper http.RoundTripper) (*content.Container, *config.GFlowsContext, *GoTemplateEngine) {
+	if config == "" {
+		config = "templates:\n  engine: gotemplate"
+	}
+	ioContainer, context, _ := fixtures.NewTestContext(config)
+	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
+	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
+	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
+	templateEngine := NewGoTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env)
+	return container, context, templateEngine
+}
+
+func TestGetGoTemplateWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine := newGoTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte(exampleGoTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.tmpl", []byte(exampleGoTemplateLib), 0644)
+	fs.WriteFile(".gflows/libs/values.yml", []byte("main_branch: develop\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, "test", definitions[0].Name)
+	assert.Equal(t, ".github/workflows/test.yml", definitions[0].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml.tmpl", exampleGoTemplateWorkflow), definitions[0].Content)
+}
+
+func TestGetGoTemplateWorkflowDefinitionsWithPackages(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: gotemplate",
+		"  defaults:",
+		"    dependencies:",
+		"    - /path/to/my-lib",
+	}, "\n")
+	container, _, templateEngine := newGoTemplateEngine(config, fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte(exampleGoTemplate), 0644)
+	fs.WriteFile(".gflows/libs/values.yml", []byte("main_branch: develop\n"), 0644)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/steps.tmpl", "libs/values.yml", "workflows/lib-workflow.yml.tmpl"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/steps.tmpl", exampleGoTemplateLib)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/values.yml", "main_branch: master\n")
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/lib-workflow.yml.tmpl", "name: {{ .Workflow }}\n")
+	lib, _ := templateEngine.env.LoadDependency("/path/to/my-lib")
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, filepath.Join(lib.LocalDir, "workflows/lib-workflow.yml.tmpl"), definitions[0].Source)
+	assert.Equal(t, fixtures.GeneratedWorkflow("my-lib/workflows/lib-workflow.yml.tmpl", "name: lib-workflow\n"), definitions[0].Content)
+	// values in the context libs take precedence over those in dependencies
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml.tmpl", exampleGoTemplateWorkflow), definitions[1].Content)
+}
+
+func TestGetGoTemplateWorkflowDefinitionsWithErrors(t *testing.T) {
+	container, _, templateEngine := newGoTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte("name: {{ include \"missing\" . }}\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{`template: test.yml.tmpl:1:9: executing "test.yml.tmpl" at <include "missing" .>: error calling include: template: no template "missing" associated with template "test.yml.tmpl"`},
+	}, definitions[0].Status)
+}
+
+func TestGetGoTemplateWorkflowDefinitionsWithNestedPartials(t *testing.T) {
+	container, _, templateEngine := newGoTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte(`name: {{ template "common/name.tmpl" }}`), 0644)
+	fs.WriteFile(".gflows/libs/name.tmpl", []byte("name"), 0644)
+	fs.WriteFile(".gflows/libs/common/name.tmpl", []byte("common-name"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml.tmpl", "name: common-name\n"), definitions[0].Content)
+}
+
+func TestGetGoTemplateWorkflowDefinitionsWithDuplicatePartials(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: gotemplate",
+		"  defaults:",
+		"    dependencies:",
+		"    - /path/to/my-lib",
+	}, "\n")
+	container, _, templateEngine := newGoTemplateEngine(config, fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte(exampleGoTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.tmpl", []byte(exampleGoTemplateLib), 0644)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/steps.tmpl"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/steps.tmpl", exampleGoTemplateLib)
+	lib, _ := templateEngine.env.LoadDependency("/path/to/my-lib")
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{fmt.Sprintf(`partial "steps.tmpl" is defined by both %s and %s`, filepath.Join(lib.LocalDir, "libs/steps.tmpl"), ".gflows/libs/steps.tmpl")},
+	}, definitions[0].Status)
+}
+
+func TestGetGoTemplateObservableSources(t *testing.T) {
+	container, _, templateEngine := newGoTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml.tmpl", []byte(exampleGoTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/invalid.ext", []byte(exampleGoTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.tmpl", []byte(exampleGoTemplateLib), 0644)
+	fs.WriteFile(".gflows/libs/values.yml", []byte("main_branch: develop\n"), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		".gflows/workflows/test.yml.tmpl",
+		".gflows/libs/steps.tmpl",
+		".gflows/libs/values.yml",
+	}, sources)
+}
+
+func TestImportGoTemplateWorkflow(t *testing.T) {
+	container, _, templateEngine := newGoTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	workflowContent := exampleGoTemplateWorkflow + "    - run: docker ps --format '{{.ID}}'\n"
+	fs.WriteFile(".github/workflows/test.yml", []byte(workflowContent), 0644)
+
+	templatePath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/test.yml"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/test.yml.tmpl", templatePath)
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml.tmpl", workflowContent), definitions[0].Content)
+}
@@ -0,0 +1,82 @@
+package jsonnet
+
+import (
+	"strings"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/jbrunton/gflows/workflow"
+)
+
+// ErrorRecorder - an ErrorFormatter which records the errors it formats. The VM only returns formatted errors, so this
+// gives access to their locations.
+type ErrorRecorder struct {
+	gojsonnet.ErrorFormatter
+	Err error
+}
+
+func NewErrorRecorder(formatter gojsonnet.ErrorFormatter) *ErrorRecorder {
+	return &ErrorRecorder{ErrorFormatter: formatter}
+}
+
+func (recorder *ErrorRecorder) Format(err error) string {
+	recorder.Err = err
+	return recorder.ErrorFormatter.Format(err)
+}
+
+// staticError - matches the (internal) StaticError type for syntax and other static errors
+type staticError interface {
+	Error() string
+	Loc() ast.LocationRange
+}
+
+// GetDiagnostics - returns diagnostics for an error recorded by an ErrorRecorder, or nil if it wasn't a runtime or
+// static error
+func GetDiagnostics(err error) []workflow.Diagnostic {
+	switch err := err.(type) {
+	case gojsonnet.RuntimeError:
+		diagnostic := workflow.Diagnostic{Message: err.Msg}
+		// The innermost frame comes last, so iterate in reverse. Frames without locations or in the standard library
+		// aren't helpful, so skip them.
+		for i := len(err.StackTrace) - 1; i >= 0; i-- {
+			frame := err.StackTrace[i]
+			if !frame.Loc.IsSet() || frame.Loc.FileName == "<std>" {
+				continue
+			}
+			if diagnostic.File == "" {
+				setLocation(&diagnostic, frame.Loc)
+			}
+			description := frame.Loc.String()
+			if frame.Name != "" {
+				description += " " + frame.Name
+			}
+			diagnostic.Stack = append(diagnostic.Stack, description)
+		}
+		return []workflow.Diagnostic{diagnostic}
+	case staticError:
+		loc := err.Loc()
+		message := strings.TrimSpace(err.Error())
+		if loc.IsSet() {
+			message = strings.TrimSpace(strings.TrimPrefix(message, loc.String()))
+		}
+		diagnostic := workflow.Diagnostic{Message: message}
+		setLocation(&diagnostic, loc)
+		return []workflow.Diagnostic{diagnostic}
+	}
+	return nil
+}
+
+func setLocation(diagnostic *workflow.Diagnostic, loc ast.LocationRange) {
+	diagnostic.File = loc.FileName
+	if !loc.IsSet() {
+		return
+	}
+	diagnostic.Line = loc.Begin.Line
+	diagnostic.Column = loc.Begin.Column
+	if loc.End.Line == loc.Begin.Line {
+		diagnostic.Length = loc.End.Column - loc.Begin.Column
+	}
+	if loc.File != nil && loc.Begin.Line <= len(loc.File.Lines) {
+		diagnostic.Snippet = strings.TrimRight(loc.File.Lines[loc.Begin.Line-1], "\r\n")
+	}
+}
@@ -0,0 +1,51 @@
+package jsonnet
+
+import (
+	"testing"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDiagnostics(snippet string) []workflow.Diagnostic {
+	vm := gojsonnet.MakeVM()
+	recorder := NewErrorRecorder(vm.ErrorFormatter)
+	vm.ErrorFormatter = recorder
+	vm.EvaluateSnippet("test.jsonnet", snippet)
+	return GetDiagnostics(recorder.Err)
+}
+
+func TestGetRuntimeErrorDiagnostics(t *testing.T) {
+	diagnostics := getDiagnostics("local f(x) = error 'bad ' + x;\n{ foo: f('bar') }")
+
+	assert.Equal(t, []workflow.Diagnostic{{
+		File:    "test.jsonnet",
+		Line:    1,
+		Column:  14,
+		Length:  16,
+		Message: "bad bar",
+		Snippet: "local f(x) = error 'bad ' + x;",
+		Stack: []string{
+			"test.jsonnet:1:14-30 function <f>",
+			"test.jsonnet:2:8-16 object <anonymous>",
+		},
+	}}, diagnostics)
+}
+
+func TestGetStaticErrorDiagnostics(t *testing.T) {
+	diagnostics := getDiagnostics("{\n  foo: {,\n}")
+
+	assert.Equal(t, []workflow.Diagnostic{{
+		File:    "test.jsonnet",
+		Line:    2,
+		Column:  9,
+		Length:  1,
+		Message: "Unexpected: \",\" while parsing field definition",
+		Snippet: "  foo: {,",
+	}}, diagnostics)
+}
+
+func TestGetOtherErrorDiagnostics(t *testing.T) {
+	assert.Nil(t, GetDiagnostics(nil))
+}
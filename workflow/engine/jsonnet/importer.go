@@ -0,0 +1,191 @@
+package jsonnet
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/spf13/afero"
+)
+
+// unpinnedCacheExpiry - how long to use cached copies of remote files without checksums before downloading them
+// again
+const unpinnedCacheExpiry = time.Hour
+
+// checksumPrefix - the prefix for checksums given in the fragment of an import URL, e.g.
+// import "https://example.com/lib.libsonnet#sha256=<checksum>"
+const checksumPrefix = "sha256="
+
+// RemoteImporter - a jsonnet importer which resolves URLs with the content reader, and delegates local imports to a
+// FileImporter. Relative imports in remote files are resolved relative to their URL.
+//
+// Remote files are only downloaded once. If a file has a sha256 checksum, either in the fragment of its URL or in
+// checksums, then its content is verified and cached in cacheDir (which is safe since the content can't change).
+// Unpinned files are cached in cacheDir too, keyed by their URL, but are downloaded again once the cached copy is
+// older than unpinnedCacheExpiry.
+// Pinning a file doesn't pin the files it imports: relative imports are only verified if they're pinned too (e.g. in
+// templates.checksums).
+type RemoteImporter struct {
+	fs           *afero.Afero
+	reader       *content.Reader
+	fileImporter *gojsonnet.FileImporter
+	cacheDir     string
+	checksums    map[string]string
+	contents     map[string]gojsonnet.Contents
+}
+
+func NewRemoteImporter(fs *afero.Afero, reader *content.Reader, cacheDir string, checksums map[string]string) *RemoteImporter {
+	return &RemoteImporter{
+		fs:           fs,
+		reader:       reader,
+		fileImporter: &gojsonnet.FileImporter{},
+		cacheDir:     cacheDir,
+		checksums:    checksums,
+		contents:     make(map[string]gojsonnet.Contents),
+	}
+}
+
+// WithJPaths - returns an importer which searches the given paths for local imports, and shares downloaded files
+// with this importer
+func (importer *RemoteImporter) WithJPaths(jpaths []string) *RemoteImporter {
+	result := *importer
+	result.fileImporter = &gojsonnet.FileImporter{JPaths: jpaths}
+	return &result
+}
+
+// Import - implements gojsonnet.Importer
+func (importer *RemoteImporter) Import(importedFrom, importedPath string) (gojsonnet.Contents, string, error) {
+	if !pkg.IsRemotePath(importedPath) && !pkg.IsRemotePath(importedFrom) {
+		return importer.fileImporter.Import(importedFrom, importedPath)
+	}
+
+	importURL, err := resolveURL(importedFrom, importedPath)
+	if err != nil {
+		return gojsonnet.Contents{}, "", err
+	}
+
+	if importURL.Fragment != "" && !strings.HasPrefix(importURL.Fragment, checksumPrefix) {
+		return gojsonnet.Contents{}, "", fmt.Errorf("unexpected fragment in %s, expected #%s<checksum>", importedPath, checksumPrefix)
+	}
+	checksum := strings.TrimPrefix(importURL.Fragment, checksumPrefix)
+	importURL.Fragment = ""
+	foundAt := importURL.String()
+	if checksum == "" {
+		checksum = importer.checksums[foundAt]
+	}
+
+	contents, err := importer.read(foundAt, strings.ToLower(checksum))
+	return contents, foundAt, err
+}
+
+func (importer *RemoteImporter) read(path string, checksum string) (gojsonnet.Contents, error) {
+	if contents, ok := importer.contents[path]; ok {
+		// the content may have been downloaded for an unpinned import, so still needs to be verified
+		if checksum != "" {
+			if err := verifyChecksum(path, contents.String(), checksum); err != nil {
+				return gojsonnet.Contents{}, err
+			}
+		}
+		return contents, nil
+	}
+
+	var data string
+	var err error
+	if checksum != "" {
+		data, err = importer.readVerified(path, checksum)
+	} else {
+		data, err = importer.readCached(path)
+	}
+	if err != nil {
+		return gojsonnet.Contents{}, err
+	}
+
+	contents := gojsonnet.MakeContents(data)
+	importer.contents[path] = contents
+	return contents, nil
+}
+
+// readVerified - returns the content with the given checksum, either from the cache or by downloading it
+func (importer *RemoteImporter) readVerified(path string, checksum string) (string, error) {
+	cachePath := filepath.Join(importer.cacheDir, checksum)
+	if cached, err := importer.fs.ReadFile(cachePath); err == nil && sha256Hex(string(cached)) == checksum {
+		return string(cached), nil
+	}
+
+	data, err := importer.reader.ReadContent(path)
+	if err != nil {
+		return "", err
+	}
+	if err := verifyChecksum(path, data, checksum); err != nil {
+		return "", err
+	}
+
+	if err := importer.fs.MkdirAll(importer.cacheDir, 0755); err != nil {
+		return "", err
+	}
+	if err := importer.fs.WriteFile(cachePath, []byte(data), 0644); err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+// readCached - returns the content of an unpinned file, either from the cache (if it hasn't expired) or by
+// downloading it
+func (importer *RemoteImporter) readCached(path string) (string, error) {
+	cacheDir := filepath.Join(importer.cacheDir, "urls")
+	cachePath := filepath.Join(cacheDir, sha256Hex(path))
+	if info, err := importer.fs.Stat(cachePath); err == nil && time.Since(info.ModTime()) < unpinnedCacheExpiry {
+		if cached, err := importer.fs.ReadFile(cachePath); err == nil {
+			return string(cached), nil
+		}
+	}
+
+	data, err := importer.reader.ReadContent(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := importer.fs.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	if err := importer.fs.WriteFile(cachePath, []byte(data), 0644); err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+// resolveURL - resolves importedPath, which may be relative to importedFrom
+func resolveURL(importedFrom string, importedPath string) (*url.URL, error) {
+	importURL, err := url.Parse(importedPath)
+	if err != nil {
+		return nil, err
+	}
+	if pkg.IsRemotePath(importedPath) {
+		return importURL, nil
+	}
+	if filepath.IsAbs(importedPath) {
+		return nil, fmt.Errorf("cannot import local path %s from %s", importedPath, importedFrom)
+	}
+	baseURL, err := url.Parse(importedFrom)
+	if err != nil {
+		return nil, err
+	}
+	return baseURL.ResolveReference(importURL), nil
+}
+
+func verifyChecksum(path string, data string, checksum string) error {
+	if actual := sha256Hex(data); actual != checksum {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, checksum, actual)
+	}
+	return nil
+}
+
+func sha256Hex(data string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(data)))
+}
@@ -0,0 +1,144 @@
+package jsonnet
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+// sha256 of "{ greeting: 'hello' }"
+const greetingChecksum = "b1079b70a0f2528a552b4f2ebb532f896265c21240158f65b42122970bcf2dbd"
+
+func newRemoteImporter(checksums map[string]string) (*afero.Afero, *fixtures.TestRoundTripper, *RemoteImporter) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	roundTripper := fixtures.NewTestRoundTripper()
+	reader := content.NewReader(fs, &http.Client{Transport: roundTripper})
+	return fs, roundTripper, NewRemoteImporter(fs, reader, "cache", checksums)
+}
+
+func evaluateImports(importer *RemoteImporter, snippet string) (string, error) {
+	vm := gojsonnet.MakeVM()
+	vm.Importer(importer)
+	return vm.EvaluateSnippet("test.jsonnet", snippet)
+}
+
+func TestRemoteImports(t *testing.T) {
+	_, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib/main.libsonnet", "{ steps: import 'steps/checkout.libsonnet' }")
+	roundTripper.StubBody("https://example.com/lib/steps/checkout.libsonnet", "[{ uses: 'actions/checkout@v2' }]")
+
+	output, err := evaluateImports(importer, "(import 'https://example.com/lib/main.libsonnet').steps")
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{ "uses": "actions/checkout@v2" }]`, output)
+}
+
+func TestRemoteImportsAreDownloadedOnce(t *testing.T) {
+	_, roundTripper, importer := newRemoteImporter(nil)
+	// The stubbed response body can only be read once
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'hello' }")
+
+	for i := 0; i < 2; i++ {
+		output, err := evaluateImports(importer.WithJPaths([]string{}), "(import 'https://example.com/lib.libsonnet').greeting")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\"hello\"\n", output)
+	}
+}
+
+func TestRemoteImportsUseCache(t *testing.T) {
+	fs, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'hello' }")
+
+	output, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet').greeting")
+	assert.NoError(t, err)
+	assert.Equal(t, "\"hello\"\n", output)
+	cachePath := "cache/urls/" + sha256Hex("https://example.com/lib.libsonnet")
+	cached, _ := fs.ReadFile(cachePath)
+	assert.Equal(t, "{ greeting: 'hello' }", string(cached))
+
+	// the stubbed response can only be read once, so a new importer has to use the cache
+	importer = NewRemoteImporter(fs, content.NewReader(fs, &http.Client{Transport: fixtures.NewTestRoundTripper()}), "cache", nil)
+	output, err = evaluateImports(importer, "(import 'https://example.com/lib.libsonnet').greeting")
+	assert.NoError(t, err)
+	assert.Equal(t, "\"hello\"\n", output)
+}
+
+func TestRemoteImportsCacheExpires(t *testing.T) {
+	fs, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'goodbye' }")
+	cachePath := "cache/urls/" + sha256Hex("https://example.com/lib.libsonnet")
+	fs.WriteFile(cachePath, []byte("{ greeting: 'hello' }"), 0644)
+	expired := time.Now().Add(-unpinnedCacheExpiry)
+	fs.Chtimes(cachePath, expired, expired)
+
+	output, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet').greeting")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\"goodbye\"\n", output)
+	cached, _ := fs.ReadFile(cachePath)
+	assert.Equal(t, "{ greeting: 'goodbye' }", string(cached))
+}
+
+func TestPinnedRemoteImports(t *testing.T) {
+	fs, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'hello' }")
+
+	output, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet#sha256="+greetingChecksum+"').greeting")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\"hello\"\n", output)
+	cached, _ := fs.ReadFile("cache/" + greetingChecksum)
+	assert.Equal(t, "{ greeting: 'hello' }", string(cached))
+}
+
+func TestPinnedRemoteImportsFromConfig(t *testing.T) {
+	_, roundTripper, importer := newRemoteImporter(map[string]string{
+		"https://example.com/lib.libsonnet": greetingChecksum,
+	})
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'goodbye' }")
+
+	_, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet').greeting")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for https://example.com/lib.libsonnet: expected "+greetingChecksum)
+}
+
+func TestPinnedRemoteImportsUseCache(t *testing.T) {
+	fs, _, importer := newRemoteImporter(nil)
+	fs.WriteFile("cache/"+greetingChecksum, []byte("{ greeting: 'hello' }"), 0644)
+
+	output, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet#sha256="+greetingChecksum+"').greeting")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\"hello\"\n", output)
+}
+
+func TestPinnedRemoteImportsVerifyDownloadedContent(t *testing.T) {
+	_, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "{ greeting: 'goodbye' }")
+
+	_, err := evaluateImports(importer, "(import 'https://example.com/lib.libsonnet').greeting")
+	assert.NoError(t, err)
+
+	_, err = evaluateImports(importer, "(import 'https://example.com/lib.libsonnet#sha256="+greetingChecksum+"').greeting")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch for https://example.com/lib.libsonnet: expected "+greetingChecksum)
+}
+
+func TestRemoteImportErrors(t *testing.T) {
+	_, roundTripper, importer := newRemoteImporter(nil)
+	roundTripper.StubBody("https://example.com/lib.libsonnet", "import '/etc/passwd'")
+
+	_, err := evaluateImports(importer, "import 'https://example.com/lib.libsonnet#foo'")
+	assert.Contains(t, err.Error(), "unexpected fragment in https://example.com/lib.libsonnet#foo, expected #sha256=<checksum>")
+
+	_, err = evaluateImports(importer, "import 'https://example.com/lib.libsonnet'")
+	assert.Contains(t, err.Error(), "cannot import local path /etc/passwd from https://example.com/lib.libsonnet")
+}
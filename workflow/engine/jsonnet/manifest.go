@@ -0,0 +1,330 @@
+package jsonnet
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jbrunton/gflows/yamlutil"
+	"gopkg.in/yaml.v3"
+)
+
+// layoutStyles - the names of the styles which may be given in a layout
+var layoutStyles = map[string]yaml.Style{
+	"flow":    yaml.FlowStyle,
+	"single":  yaml.SingleQuotedStyle,
+	"double":  yaml.DoubleQuotedStyle,
+	"literal": yaml.LiteralStyle,
+	"folded":  yaml.FoldedStyle,
+}
+
+// ManifestYamlDoc - converts a value to YAML, like std.manifestYamlDoc, but formatted as gflows formats workflows, and
+// with the key order, comments and styles given by the layout. The layout maps JSON pointers (e.g. "/jobs/test" or
+// "/jobs/test/steps/0", and "" for the document) to objects with any of these fields:
+//   - order: the keys of the mapping, in order (any other keys follow in sorted order)
+//   - head, line, foot: the comments above, at the end of and below the value
+//   - style: one of flow, single, double, literal or folded
+func ManifestYamlDoc(value interface{}, layout map[string]interface{}) (string, error) {
+	root, err := buildNode(value, "", layout)
+	if err != nil {
+		return "", err
+	}
+	document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root.value}}
+	document.HeadComment = root.head
+	document.FootComment = root.foot
+	root.value.LineComment = joinComments(" ", root.value.LineComment, root.line)
+	yamlutil.QuoteYaml11Bools(document)
+	content, err := yamlutil.EncodeNode(document)
+	if err != nil {
+		return "", err
+	}
+	// like std.manifestYamlDoc, the output isn't terminated with a newline
+	return strings.TrimSuffix(content, "\n"), nil
+}
+
+// layoutNode - a node built for a value, with the comments given for it in the layout
+type layoutNode struct {
+	value *yaml.Node
+	head  string
+	line  string
+	foot  string
+}
+
+func buildNode(value interface{}, path string, layout map[string]interface{}) (*layoutNode, error) {
+	entry, err := getLayoutEntry(layout, path)
+	if err != nil {
+		return nil, err
+	}
+	node := &layoutNode{value: &yaml.Node{}}
+	for _, field := range []struct {
+		name   string
+		target *string
+	}{{"head", &node.head}, {"line", &node.line}, {"foot", &node.foot}} {
+		if *field.target, err = getLayoutString(entry, path, field.name); err != nil {
+			return nil, err
+		}
+	}
+	styleName, err := getLayoutString(entry, path, "style")
+	if err != nil {
+		return nil, err
+	}
+	style, ok := layoutStyles[styleName]
+	if styleName != "" && !ok {
+		return nil, fmt.Errorf("layout %q: unknown style %q", path, styleName)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys, err := getOrderedKeys(value, entry, path)
+		if err != nil {
+			return nil, err
+		}
+		node.value.Kind = yaml.MappingNode
+		node.value.Tag = "!!map"
+		if style == yaml.FlowStyle {
+			node.value.Style = style
+		}
+		for _, key := range keys {
+			child, err := buildNode(value[key], path+"/"+escapePointer(key), layout)
+			if err != nil {
+				return nil, err
+			}
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+			keyNode.HeadComment = child.head
+			keyNode.FootComment = child.foot
+			if child.value.Kind == yaml.ScalarNode {
+				child.value.LineComment = child.line
+			} else {
+				keyNode.LineComment = child.line
+			}
+			node.value.Content = append(node.value.Content, keyNode, child.value)
+		}
+	case []interface{}:
+		node.value.Kind = yaml.SequenceNode
+		node.value.Tag = "!!seq"
+		if style == yaml.FlowStyle {
+			node.value.Style = style
+		}
+		for i, item := range value {
+			child, err := buildNode(item, fmt.Sprintf("%s/%d", path, i), layout)
+			if err != nil {
+				return nil, err
+			}
+			child.value.HeadComment = child.head
+			child.value.LineComment = child.line
+			child.value.FootComment = child.foot
+			node.value.Content = append(node.value.Content, child.value)
+		}
+	case string:
+		node.value.Kind = yaml.ScalarNode
+		node.value.Tag = "!!str"
+		node.value.Value = value
+		if style != yaml.FlowStyle {
+			node.value.Style = style
+		}
+	case float64:
+		node.value.Kind = yaml.ScalarNode
+		node.value.Value = strconv.FormatFloat(value, 'f', -1, 64)
+		node.value.Tag = "!!float"
+		if value == float64(int64(value)) {
+			node.value.Tag = "!!int"
+		}
+	case bool:
+		node.value.Kind = yaml.ScalarNode
+		node.value.Tag = "!!bool"
+		node.value.Value = strconv.FormatBool(value)
+	case nil:
+		node.value.Kind = yaml.ScalarNode
+		node.value.Tag = "!!null"
+		node.value.Value = "null"
+	default:
+		return nil, fmt.Errorf("can't manifest %v as YAML", value)
+	}
+	return node, nil
+}
+
+func getLayoutEntry(layout map[string]interface{}, path string) (map[string]interface{}, error) {
+	entry, ok := layout[path]
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	fields, ok := entry.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("layout %q: expected an object, got %v", path, entry)
+	}
+	return fields, nil
+}
+
+func getLayoutString(entry map[string]interface{}, path string, field string) (string, error) {
+	value, ok := entry[field]
+	if !ok || value == nil {
+		return "", nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("layout %q: expected %s to be a string, got %v", path, field, value)
+	}
+	return str, nil
+}
+
+// getOrderedKeys - returns the keys of the object in the order given by the layout, followed by any others in sorted
+// order. Keys in the layout which the object doesn't have are ignored, so that fields can be removed from templates.
+func getOrderedKeys(object map[string]interface{}, entry map[string]interface{}, path string) ([]string, error) {
+	keys := []string{}
+	included := make(map[string]bool)
+	if order, ok := entry["order"]; ok {
+		orderedKeys, ok := order.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("layout %q: expected order to be an array, got %v", path, order)
+		}
+		for _, key := range orderedKeys {
+			key, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("layout %q: expected order to contain strings, got %v", path, key)
+			}
+			if _, exists := object[key]; exists && !included[key] {
+				keys = append(keys, key)
+				included[key] = true
+			}
+		}
+	}
+	otherKeys := []string{}
+	for key := range object {
+		if !included[key] {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	return append(keys, otherKeys...), nil
+}
+
+// escapePointer - escapes a key for use in a JSON pointer (see RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// MarshalLayout - returns the layout for ManifestYamlDoc which reproduces the key order, comments and styles of the
+// YAML document, as a Jsonnet object. Entries are only included for values which need them.
+func MarshalLayout(document *yaml.Node) (string, error) {
+	builder := &layoutBuilder{}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return "{}", nil
+	}
+	if err := builder.add(document.Content[0], "", document.HeadComment, "", document.FootComment); err != nil {
+		return "", err
+	}
+	if len(builder.lines) == 0 {
+		return "{}", nil
+	}
+	return "{\n" + strings.Join(builder.lines, ",\n") + "\n}", nil
+}
+
+type layoutBuilder struct {
+	lines []string
+}
+
+func (builder *layoutBuilder) add(node *yaml.Node, path string, head string, line string, foot string) error {
+	if node.Kind == yaml.AliasNode {
+		return builder.add(node.Alias, path, head, line, foot)
+	}
+
+	fields := []string{}
+	switch node.Kind {
+	case yaml.MappingNode:
+		keys := []string{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keys = append(keys, node.Content[i].Value)
+		}
+		if !sort.StringsAreSorted(keys) {
+			orderedKeys := []string{}
+			for _, key := range keys {
+				orderedKeys = append(orderedKeys, marshalString(key))
+			}
+			fields = append(fields, fmt.Sprintf("order: [%s]", strings.Join(orderedKeys, ", ")))
+		}
+	case yaml.ScalarNode:
+		style, err := getScalarStyle(node)
+		if err != nil {
+			return err
+		}
+		if style != "" {
+			fields = append(fields, "style: "+marshalString(style))
+		}
+	}
+	if node.Kind != yaml.ScalarNode && node.Style&yaml.FlowStyle != 0 {
+		fields = append(fields, "style: "+marshalString("flow"))
+	}
+	for _, comment := range []struct {
+		name  string
+		value string
+	}{{"head", head}, {"line", line}, {"foot", foot}} {
+		if comment.value != "" {
+			fields = append(fields, comment.name+": "+marshalString(comment.value))
+		}
+	}
+	if len(fields) > 0 {
+		builder.lines = append(builder.lines, fmt.Sprintf("  %s: { %s }", marshalKey(path), strings.Join(fields, ", ")))
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			err := builder.add(value, path+"/"+escapePointer(key.Value),
+				joinComments("\n", key.HeadComment, value.HeadComment),
+				joinComments(" ", key.LineComment, value.LineComment),
+				joinComments("\n", key.FootComment, value.FootComment))
+			if err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			err := builder.add(item, fmt.Sprintf("%s/%d", path, i), item.HeadComment, item.LineComment, item.FootComment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getScalarStyle - returns the name of the scalar's style, if it's a string whose style differs from the one it would
+// be encoded with by default
+func getScalarStyle(node *yaml.Node) (string, error) {
+	if node.Tag != "!!str" || node.Style == 0 {
+		return "", nil
+	}
+	defaultNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: node.Tag, Value: node.Value}
+	yamlutil.QuoteYaml11Bools(defaultNode)
+	defaultContent, err := yamlutil.EncodeNode(defaultNode)
+	if err != nil {
+		return "", err
+	}
+	styledNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: node.Tag, Value: node.Value, Style: node.Style}
+	styledContent, err := yamlutil.EncodeNode(styledNode)
+	if err != nil {
+		return "", err
+	}
+	if defaultContent == styledContent {
+		return "", nil
+	}
+	for name, style := range layoutStyles {
+		if node.Style&style != 0 && style != yaml.FlowStyle {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// joinComments - joins the comments which aren't empty with the separator
+func joinComments(separator string, comments ...string) string {
+	nonEmpty := []string{}
+	for _, comment := range comments {
+		if comment != "" {
+			nonEmpty = append(nonEmpty, comment)
+		}
+	}
+	return strings.Join(nonEmpty, separator)
+}
@@ -0,0 +1,61 @@
+package jsonnet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestManifestYamlDocWithLayout(t *testing.T) {
+	original := strings.Join([]string{
+		`# Runs the tests`,
+		`"on": push`,
+		`jobs:`,
+		`  test:`,
+		`    # keep this up to date`,
+		`    runs-on: ubuntu-latest`,
+		`    env: {GO111MODULE: "on"}`,
+		`    steps:`,
+		`    - run: make test # runs all the tests`,
+		`    - uses: actions/checkout@v2`,
+		`      with:`,
+		`        ref: 'main'`,
+		`# end of the workflow`,
+		``,
+	}, "\n")
+	var document yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(original), &document))
+	workflow, err := MarshalNode(&document)
+	assert.NoError(t, err)
+	layout, err := MarshalLayout(&document)
+	assert.NoError(t, err)
+
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	output, _, err := evaluateNative(t, fs, "std.native('manifestYamlDoc')("+workflow+", "+layout+")")
+
+	assert.NoError(t, err)
+	var result string
+	assert.NoError(t, yaml.Unmarshal([]byte(output), &result))
+	assert.Equal(t, strings.TrimSuffix(original, "\n"), result)
+}
+
+func TestManifestYamlDocWithoutLayout(t *testing.T) {
+	result, err := ManifestYamlDoc(map[string]interface{}{
+		"on":   "push",
+		"jobs": map[string]interface{}{"test": map[string]interface{}{"timeout-minutes": float64(10)}},
+	}, map[string]interface{}{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jobs:\n  test:\n    timeout-minutes: 10\n\"on\": push", result)
+}
+
+func TestManifestYamlDocWithInvalidLayout(t *testing.T) {
+	_, err := ManifestYamlDoc(map[string]interface{}{"on": "push"}, map[string]interface{}{
+		"": map[string]interface{}{"style": "bold"},
+	})
+
+	assert.EqualError(t, err, `layout "": unknown style "bold"`)
+}
@@ -0,0 +1,151 @@
+package jsonnet
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/spf13/afero"
+)
+
+// NativeFunctions - returns the native functions available to templates via std.native. Paths are relative to
+// rootDir, and may not refer to files outside it. onRead is called with the resolved path of any file or directory
+// read by the functions.
+func NativeFunctions(fs *afero.Afero, rootDir string, onRead func(path string)) []*gojsonnet.NativeFunction {
+	return []*gojsonnet.NativeFunction{
+		{
+			Name:   "sha256File",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []interface{}) (interface{}, error) {
+				content, err := readFile(fs, rootDir, onRead, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+			},
+		},
+		{
+			Name:   "readFile",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []interface{}) (interface{}, error) {
+				content, err := readFile(fs, rootDir, onRead, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return string(content), nil
+			},
+		},
+		{
+			Name:   "listDir",
+			Params: ast.Identifiers{"path"},
+			Func: func(args []interface{}) (interface{}, error) {
+				path, err := resolvePath(fs, rootDir, args[0])
+				if err != nil {
+					return nil, err
+				}
+				infos, err := fs.ReadDir(path)
+				if err != nil {
+					return nil, err
+				}
+				onRead(path)
+				names := []string{}
+				for _, info := range infos {
+					names = append(names, info.Name())
+				}
+				sort.Strings(names)
+				entries := []interface{}{}
+				for _, name := range names {
+					entries = append(entries, name)
+				}
+				return entries, nil
+			},
+		},
+		{
+			Name:   "regexMatch",
+			Params: ast.Identifiers{"pattern", "str"},
+			Func: func(args []interface{}) (interface{}, error) {
+				pattern, ok := args[0].(string)
+				if !ok {
+					return nil, fmt.Errorf("regexMatch: expected pattern to be a string, got %v", args[0])
+				}
+				str, ok := args[1].(string)
+				if !ok {
+					return nil, fmt.Errorf("regexMatch: expected str to be a string, got %v", args[1])
+				}
+				return regexp.MatchString(pattern, str)
+			},
+		},
+		{
+			Name:   "manifestYamlDoc",
+			Params: ast.Identifiers{"value", "layout"},
+			Func: func(args []interface{}) (interface{}, error) {
+				layout, ok := args[1].(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("manifestYamlDoc: expected layout to be an object, got %v", args[1])
+				}
+				return ManifestYamlDoc(args[0], layout)
+			},
+		},
+	}
+}
+
+func readFile(fs *afero.Afero, rootDir string, onRead func(path string), arg interface{}) ([]byte, error) {
+	path, err := resolvePath(fs, rootDir, arg)
+	if err != nil {
+		return nil, err
+	}
+	content, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	onRead(path)
+	return content, nil
+}
+
+// resolvePath - returns the path joined to rootDir, or an error if it's absolute or outside rootDir. On the OS
+// filesystem symlinks are resolved first, so that links can't be used to read files outside rootDir.
+func resolvePath(fs *afero.Afero, rootDir string, arg interface{}) (string, error) {
+	path, ok := arg.(string)
+	if !ok {
+		return "", fmt.Errorf("expected path to be a string, got %v", arg)
+	}
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("%s: absolute paths are not permitted", path)
+	}
+	resolvedPath := filepath.Join(rootDir, path)
+	if !isWithinDir(rootDir, resolvedPath) {
+		return "", fmt.Errorf("%s: paths outside the repository are not permitted", path)
+	}
+	if _, ok := fs.Fs.(*afero.OsFs); ok {
+		realRootDir, err := filepath.EvalSymlinks(rootDir)
+		if err != nil {
+			return "", err
+		}
+		realPath, err := filepath.EvalSymlinks(resolvedPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// nothing to resolve, reading the file will fail
+				return resolvedPath, nil
+			}
+			return "", err
+		}
+		if !isWithinDir(realRootDir, realPath) {
+			return "", fmt.Errorf("%s: paths outside the repository are not permitted", path)
+		}
+	}
+	return resolvedPath, nil
+}
+
+func isWithinDir(dir string, path string) bool {
+	relPath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator))
+}
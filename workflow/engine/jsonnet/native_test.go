@@ -0,0 +1,89 @@
+package jsonnet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gojsonnet "github.com/google/go-jsonnet"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func evaluateNative(t *testing.T, fs *afero.Afero, snippet string) (string, []string, error) {
+	return evaluateNativeIn(t, fs, "repo", snippet)
+}
+
+func evaluateNativeIn(t *testing.T, fs *afero.Afero, rootDir string, snippet string) (string, []string, error) {
+	vm := gojsonnet.MakeVM()
+	read := []string{}
+	for _, function := range NativeFunctions(fs, rootDir, func(path string) { read = append(read, path) }) {
+		vm.NativeFunction(function)
+	}
+	output, err := vm.EvaluateSnippet("test.jsonnet", snippet)
+	return output, read, err
+}
+
+func TestNativeFunctions(t *testing.T) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	fs.WriteFile("repo/go.sum", []byte("hello"), 0644)
+	fs.WriteFile("repo/envs/staging.json", []byte("{}"), 0644)
+	fs.WriteFile("repo/envs/production.json", []byte("{}"), 0644)
+
+	output, read, err := evaluateNative(t, fs, `{
+		hash: std.native('sha256File')('go.sum'),
+		content: std.native('readFile')('go.sum'),
+		envs: std.native('listDir')('envs'),
+		match: std.native('regexMatch')('^prod', 'production'),
+	}`)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"hash": "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"content": "hello",
+		"envs": ["production.json", "staging.json"],
+		"match": true
+	}`, output)
+	assert.ElementsMatch(t, []string{"repo/go.sum", "repo/go.sum", "repo/envs"}, read)
+}
+
+func TestNativeFunctionsSandbox(t *testing.T) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	fs.WriteFile("secret", []byte("secret"), 0644)
+
+	_, _, err := evaluateNative(t, fs, `std.native('readFile')('../secret')`)
+	assert.Contains(t, err.Error(), "../secret: paths outside the repository are not permitted")
+
+	_, _, err = evaluateNative(t, fs, `std.native('readFile')('/secret')`)
+	assert.Contains(t, err.Error(), "/secret: absolute paths are not permitted")
+
+	_, read, err := evaluateNative(t, fs, `std.native('readFile')('foo/../../secret')`)
+	assert.Contains(t, err.Error(), "foo/../../secret: paths outside the repository are not permitted")
+	assert.Empty(t, read)
+}
+
+func TestNativeFunctionsSandboxSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gflows")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	fs := &afero.Afero{Fs: afero.NewOsFs()}
+	repoDir := filepath.Join(dir, "repo")
+	assert.NoError(t, fs.MkdirAll(filepath.Join(repoDir, "envs"), 0755))
+	fs.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0644)
+	fs.WriteFile(filepath.Join(repoDir, "envs/staging.json"), []byte("{}"), 0644)
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "secret"), filepath.Join(repoDir, "secret")))
+	assert.NoError(t, os.Symlink(dir, filepath.Join(repoDir, "parent")))
+	assert.NoError(t, os.Symlink(filepath.Join(repoDir, "envs"), filepath.Join(repoDir, "config")))
+
+	_, _, err = evaluateNativeIn(t, fs, repoDir, `std.native('readFile')('secret')`)
+	assert.Contains(t, err.Error(), "secret: paths outside the repository are not permitted")
+
+	_, _, err = evaluateNativeIn(t, fs, repoDir, `std.native('listDir')('parent')`)
+	assert.Contains(t, err.Error(), "parent: paths outside the repository are not permitted")
+
+	// links within the repository are permitted
+	output, _, err := evaluateNativeIn(t, fs, repoDir, `std.native('listDir')('config')`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["staging.json"]`, output)
+}
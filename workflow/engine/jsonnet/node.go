@@ -0,0 +1,116 @@
+package jsonnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExpressionTag - tags scalar nodes which MarshalNode should write as Jsonnet expressions, rather than as values
+const ExpressionTag = "!jsonnet"
+
+// MarshalNode - converts a YAML node to Jsonnet. The output has the same style as Marshal, but keeps the order of keys.
+// Comments aren't included, since they're given by the layout for ManifestYamlDoc instead (see MarshalLayout).
+func MarshalNode(node *yaml.Node) (string, error) {
+	printer := &nodePrinter{}
+	if err := printer.print(node, "", "", ""); err != nil {
+		return "", err
+	}
+	return strings.Join(printer.lines, "\n"), nil
+}
+
+type nodePrinter struct {
+	lines []string
+}
+
+// print - writes the node with the given indentation, prefix (e.g. the key for a field) and suffix (e.g. a comma)
+func (printer *nodePrinter) print(node *yaml.Node, indent string, prefix string, suffix string) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return printer.print(node.Alias, indent, prefix, suffix)
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			printer.lines = append(printer.lines, indent+prefix+"null"+suffix)
+			return nil
+		}
+		return printer.print(node.Content[0], indent, prefix, suffix)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			printer.lines = append(printer.lines, indent+prefix+"{}"+suffix)
+			return nil
+		}
+		printer.lines = append(printer.lines, indent+prefix+"{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			separator := ","
+			if i+2 >= len(node.Content) {
+				separator = ""
+			}
+			if err := printer.print(value, indent+"  ", marshalKey(key.Value)+": ", separator); err != nil {
+				return err
+			}
+		}
+		printer.lines = append(printer.lines, indent+"}"+suffix)
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			printer.lines = append(printer.lines, indent+prefix+"[]"+suffix)
+			return nil
+		}
+		printer.lines = append(printer.lines, indent+prefix+"[")
+		for i, item := range node.Content {
+			separator := ","
+			if i+1 >= len(node.Content) {
+				separator = ""
+			}
+			if err := printer.print(item, indent+"  ", "", separator); err != nil {
+				return err
+			}
+		}
+		printer.lines = append(printer.lines, indent+"]"+suffix)
+	default:
+		value, err := marshalScalar(node)
+		if err != nil {
+			return err
+		}
+		printer.lines = append(printer.lines, indent+prefix+value+suffix)
+	}
+	return nil
+}
+
+func marshalKey(key string) string {
+	if isIdentifier([]byte(key)) {
+		return key
+	}
+	return marshalString(key)
+}
+
+// marshalScalar - returns the Jsonnet value for a scalar. Strings are used for any values which don't have a JSON
+// equivalent (e.g. timestamps).
+func marshalScalar(node *yaml.Node) (string, error) {
+	switch node.Tag {
+	case ExpressionTag:
+		return node.Value, nil
+	case "!!int", "!!float", "!!bool", "!!null":
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return "", err
+		}
+		result, err := json.Marshal(value)
+		if err == nil {
+			return string(result), nil
+		}
+	}
+	return marshalString(node.Value), nil
+}
+
+func marshalString(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		panic(err)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,65 @@
+package jsonnet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalNode(t *testing.T) {
+	// comments are given by the layout, so they're not included
+	var node yaml.Node
+	err := yaml.Unmarshal([]byte(strings.Join([]string{
+		`# Runs the tests`,
+		`on: push`,
+		`jobs:`,
+		`  test:`,
+		`    # keep this up to date`,
+		`    runs-on: ubuntu-latest`,
+		`    steps:`,
+		`    - run: make test # runs all the tests`,
+		`    - uses: actions/checkout@v2`,
+		`      with: {fetch-depth: 0, lfs: true}`,
+	}, "\n")), &node)
+	assert.NoError(t, err)
+
+	result, err := MarshalNode(&node)
+
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`{`,
+		`  on: "push",`,
+		`  jobs: {`,
+		`    test: {`,
+		`      "runs-on": "ubuntu-latest",`,
+		`      steps: [`,
+		`        {`,
+		`          run: "make test"`,
+		`        },`,
+		`        {`,
+		`          uses: "actions/checkout@v2",`,
+		`          with: {`,
+		`            "fetch-depth": 0,`,
+		`            lfs: true`,
+		`          }`,
+		`        }`,
+		`      ]`,
+		`    }`,
+		`  }`,
+		`}`,
+	}, "\n"), result)
+}
+
+func TestMarshalNodeExpressions(t *testing.T) {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: ExpressionTag, Value: "common.steps.checkout"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "<b>"},
+	}}
+
+	result, err := MarshalNode(node)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  common.steps.checkout,\n  \"<b>\"\n]", result)
+}
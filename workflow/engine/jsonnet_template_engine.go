@@ -1,9 +1,11 @@
 package engine
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/jbrunton/gflows/io/content"
@@ -16,6 +18,8 @@ import (
 	"github.com/jbrunton/gflows/env"
 	"github.com/jbrunton/gflows/workflow/engine/jsonnet"
 	"github.com/spf13/afero"
+	"github.com/thoas/go-funk"
+	"gopkg.in/yaml.v3"
 )
 
 type JsonnetTemplateEngine struct {
@@ -23,17 +27,30 @@ type JsonnetTemplateEngine struct {
 	context       *config.GFlowsContext
 	contentWriter *content.Writer
 	env           *env.GFlowsEnv
+	importer      *jsonnet.RemoteImporter
+	nativeSources map[string]bool
 }
 
-func NewJsonnetTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv) *JsonnetTemplateEngine {
+func NewJsonnetTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentReader *content.Reader, contentWriter *content.Writer, env *env.GFlowsEnv) *JsonnetTemplateEngine {
 	return &JsonnetTemplateEngine{
 		fs:            fs,
 		context:       context,
 		contentWriter: contentWriter,
 		env:           env,
+		importer:      jsonnet.NewRemoteImporter(fs, contentReader, getJsonnetCacheDir(), context.Config.Templates.Checksums),
+		nativeSources: make(map[string]bool),
 	}
 }
 
+// getJsonnetCacheDir - returns the directory in which to cache remote imports
+func getJsonnetCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "gflows", "jsonnet")
+}
+
 func (engine *JsonnetTemplateEngine) GetObservableSources() ([]string, error) {
 	files := []string{}
 	for _, libPath := range append(
@@ -73,86 +90,301 @@ func (engine *JsonnetTemplateEngine) GetObservableSources() ([]string, error) {
 		}
 	}
 
+	nativeSources, err := engine.getNativeSources()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range nativeSources {
+		if !funk.ContainsString(files, path) {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+// getNativeSources - evaluates the templates and returns any files read by native functions
+func (engine *JsonnetTemplateEngine) getNativeSources() ([]string, error) {
+	if _, err := engine.GetWorkflowDefinitions(); err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for path := range engine.nativeSources {
+		files = append(files, path)
+	}
+	sort.Strings(files)
 	return files, nil
 }
 
 // GetWorkflowDefinitions - get workflow definitions for the given context
 func (engine *JsonnetTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	// Native sources are recorded as the templates are evaluated, so clear any from previous evaluations
+	engine.nativeSources = make(map[string]bool)
 	templates, err := engine.getWorkflowTemplates()
 	if err != nil {
 		return nil, err
 	}
 	definitions := []*workflow.Definition{}
 	for _, template := range templates {
-		workflowName := engine.getWorkflowName(template.LocalPath)
-		vm, err := engine.createVM(workflowName)
-		if err != nil {
-			return []*workflow.Definition{}, err
-		}
-		input, err := engine.fs.ReadFile(template.LocalPath)
+		templateDefinitions, err := engine.getTemplateDefinitions(template)
 		if err != nil {
 			return []*workflow.Definition{}, err
 		}
+		definitions = append(definitions, templateDefinitions...)
+	}
+
+	// Multi-output templates may generate the same file as another template
+	workflow.CheckDestinations(definitions)
+
+	return definitions, nil
+}
+
+// getTemplateDefinitions - returns the definitions generated by the template. Usually this is a single workflow, but
+// (like jsonnet -m) a template may instead return an object mapping file names to YAML strings, in which case there's
+// a definition for each file.
+func (engine *JsonnetTemplateEngine) getTemplateDefinitions(template *pkg.PathInfo) ([]*workflow.Definition, error) {
+	workflowName := engine.getWorkflowName(template.LocalPath)
+	vm, err := engine.createVM(workflowName)
+	if err != nil {
+		return nil, err
+	}
+	input, err := engine.fs.ReadFile(template.LocalPath)
+	if err != nil {
+		return nil, err
+	}
 
-		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
-		definition := &workflow.Definition{
-			Name:        workflowName,
-			Source:      template.LocalPath,
-			Destination: destinationPath,
-			Status:      workflow.ValidationResult{Valid: true},
+	errorRecorder := jsonnet.NewErrorRecorder(vm.ErrorFormatter)
+	vm.ErrorFormatter = errorRecorder
+	output, err := vm.EvaluateSnippet(template.LocalPath, string(input))
+	definition := engine.newDefinition(workflowName, template)
+	if err != nil {
+		definition.Status.Valid = false
+		definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+		definition.Status.Diagnostics = jsonnet.GetDiagnostics(errorRecorder.Err)
+		return []*workflow.Definition{definition}, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return nil, err
+	}
+	switch result := result.(type) {
+	case string:
+		// like jsonnet -S, terminate the output with a newline
+		definition.SetContent(result+"\n", template)
+	case map[string]interface{}:
+		if files, ok := getMultiOutputFiles(result); ok {
+			return engine.getMultiOutputDefinitions(template, files), nil
 		}
+		definition.Status.Valid = false
+		definition.Status.Errors = []string{getSerializationError(result)}
+	default:
+		definition.Status.Valid = false
+		definition.Status.Errors = []string{getSerializationError(result)}
+	}
+	return []*workflow.Definition{definition}, nil
+}
 
-		workflow, err := vm.EvaluateSnippet(template.LocalPath, string(input))
+// getSerializationError - returns an error explaining that the template should generate a YAML string, or an object
+// mapping file names to YAML strings
+func getSerializationError(result interface{}) string {
+	resultType := "object"
+	switch result.(type) {
+	case nil:
+		resultType = "null"
+	case bool:
+		resultType = "boolean"
+	case float64:
+		resultType = "number"
+	case []interface{}:
+		resultType = "array"
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("expected string result, got: %s", resultType),
+		"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
+	}, "\n")
+}
 
-		if err != nil {
+func (engine *JsonnetTemplateEngine) getMultiOutputDefinitions(template *pkg.PathInfo, files map[string]string) []*workflow.Definition {
+	filenames := []string{}
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	definitions := []*workflow.Definition{}
+	for _, filename := range filenames {
+		definition := engine.newDefinition(strings.TrimSuffix(filename, filepath.Ext(filename)), template)
+		if filepath.Base(filename) != filename {
 			definition.Status.Valid = false
-			errorDescription := strings.Trim(err.Error(), " \n\r")
-			if strings.Contains(err.Error(), "expected string result") {
-				errorDescription = strings.Join([]string{
-					errorDescription,
-					"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
-				}, "\n")
-			}
-			definition.Status.Errors = []string{errorDescription}
+			definition.Status.Errors = []string{fmt.Sprintf("invalid file name %q, expected a file name without a directory", filename)}
 		} else {
-			definition.SetContent(workflow, template)
+			definition.Destination = filepath.Join(engine.context.GitHubDir, "workflows/", filename)
+			// single output templates are terminated with a newline, so do the same here for consistency
+			content := files[filename]
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			definition.SetContent(content, template)
 		}
-
 		definitions = append(definitions, definition)
 	}
+	return definitions
+}
 
-	return definitions, nil
+func (engine *JsonnetTemplateEngine) newDefinition(workflowName string, template *pkg.PathInfo) *workflow.Definition {
+	return &workflow.Definition{
+		Name:        workflowName,
+		Source:      template.LocalPath,
+		Description: template.Description,
+		Destination: filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml"),
+		Status:      workflow.ValidationResult{Valid: true},
+	}
 }
 
+// getMultiOutputFiles - returns the files generated by a multi-output template, if the result maps YAML file names to
+// strings
+func getMultiOutputFiles(result map[string]interface{}) (map[string]string, bool) {
+	if len(result) == 0 {
+		return nil, false
+	}
+	files := make(map[string]string)
+	for filename, value := range result {
+		ext := filepath.Ext(filename)
+		content, ok := value.(string)
+		if !ok || (ext != ".yml" && ext != ".yaml") {
+			return nil, false
+		}
+		files[filename] = content
+	}
+	return files, true
+}
+
+// ImportWorkflow - imports the workflow as a Jsonnet object, with a layout which keeps the order of its keys and its
+// comments
 func (engine *JsonnetTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
-	workflowContent, err := engine.fs.ReadFile(wf.Path)
+	document, err := engine.parseWorkflow(wf)
 	if err != nil {
 		return "", err
 	}
 
-	normalizedContent, err := yamlutil.NormalizeWorkflow(string(workflowContent))
+	templateContent, err := getJsonnetTemplate(document, document, nil)
+	if err != nil {
+		return "", err
+	}
+	return engine.writeImportedTemplate(wf, templateContent), nil
+}
+
+// jsonnetCommonLib - the name of the lib for the steps and jobs extracted by import --extract-common
+const jsonnetCommonLib = "common.libsonnet"
+
+// ImportCommonElements - writes the common steps and jobs to a lib with a field for each kind of element
+func (engine *JsonnetTemplateEngine) ImportCommonElements(common *workflow.CommonElements) (string, error) {
+	lib := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, kind := range []struct {
+		name     string
+		elements []*workflow.CommonElement
+	}{{"steps", common.Steps}, {"jobs", common.Jobs}} {
+		if len(kind.elements) == 0 {
+			continue
+		}
+		elements := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, element := range kind.elements {
+			elements.Content = append(elements.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: element.Name}, element.Value)
+		}
+		lib.Content = append(lib.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: kind.name}, elements)
+	}
+
+	libContent, err := jsonnet.MarshalNode(lib)
 	if err != nil {
 		return "", err
 	}
 
-	jsonData, err := yamlutil.YamlToJson(string(normalizedContent))
+	libPath := filepath.Join(engine.context.LibsDir(), jsonnetCommonLib)
+	exists, err := engine.fs.Exists(libPath)
 	if err != nil {
 		return "", err
 	}
+	if exists {
+		return "", fmt.Errorf("can't extract common steps and jobs, %s already exists", libPath)
+	}
+	if err := engine.contentWriter.SafelyWriteFile(libPath, libContent+"\n"); err != nil {
+		return "", err
+	}
+	return libPath, nil
+}
 
-	json, err := jsonnet.Marshal(jsonData)
+// ImportWorkflowWithCommonElements - imports the workflow, replacing its common steps and jobs with references to the
+// common lib
+func (engine *JsonnetTemplateEngine) ImportWorkflowWithCommonElements(wf *workflow.GitHubWorkflow, common *workflow.CommonElements) (string, error) {
+	document, err := engine.parseWorkflow(wf)
 	if err != nil {
 		return "", err
 	}
 
-	templateContent := fmt.Sprintf("local workflow = %s;\n\nstd.manifestYamlDoc(workflow)\n", string(json))
+	replaced, referenced, err := common.ReplaceCommonElements(document, func(element *workflow.CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: jsonnet.ExpressionTag, Value: fmt.Sprintf("common.%s.%s", element.Kind, element.Name)}
+	})
+	if err != nil {
+		return "", err
+	}
 
+	imports := []string{}
+	if len(referenced) > 0 {
+		imports = append(imports, fmt.Sprintf("local common = import '%s';", jsonnetCommonLib))
+	}
+	templateContent, err := getJsonnetTemplate(document, replaced, imports)
+	if err != nil {
+		return "", err
+	}
+	return engine.writeImportedTemplate(wf, templateContent), nil
+}
+
+func (engine *JsonnetTemplateEngine) parseWorkflow(wf *workflow.GitHubWorkflow) (*yaml.Node, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return nil, err
+	}
+	return yamlutil.ParseWorkflowNode(workflowContent)
+}
+
+// getJsonnetTemplate - returns a template for an imported workflow. The workflow's comments, key order and string
+// styles are given by a layout taken from the original document, so that the template generates the same YAML.
+func getJsonnetTemplate(original *yaml.Node, document *yaml.Node, imports []string) (string, error) {
+	workflowContent, err := jsonnet.MarshalNode(document)
+	if err != nil {
+		return "", err
+	}
+	layoutContent, err := jsonnet.MarshalLayout(original)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{}
+	if len(imports) > 0 {
+		lines = append(lines, imports...)
+		lines = append(lines, "")
+	}
+	lines = append(lines,
+		fmt.Sprintf("local workflow = %s;", workflowContent),
+		"",
+		"// The key order, comments and styles of the imported workflow, keyed by JSON pointer",
+		fmt.Sprintf("local layout = %s;", layoutContent),
+		"",
+		"std.native('manifestYamlDoc')(workflow, layout)")
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func (engine *JsonnetTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
 	_, filename := filepath.Split(wf.Path)
 	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
-	templatePath := filepath.Join(engine.context.Dir, "workflows", templateName+".jsonnet")
-	engine.contentWriter.SafelyWriteFile(templatePath, templateContent)
+	return filepath.Join(engine.context.Dir, "workflows", templateName+".jsonnet")
+}
 
-	return templatePath, nil
+func (engine *JsonnetTemplateEngine) writeImportedTemplate(wf *workflow.GitHubWorkflow, templateContent string) string {
+	templatePath := engine.GetImportedTemplatePath(wf)
+	engine.contentWriter.SafelyWriteFile(templatePath, templateContent)
+	return templatePath
 }
 
 func (engine *JsonnetTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
@@ -205,9 +437,15 @@ func (engine *JsonnetTemplateEngine) createVM(workflowName string) (*gojsonnet.V
 	if err != nil {
 		return nil, err
 	}
-	vm.Importer(&gojsonnet.FileImporter{
-		JPaths: jpaths,
-	})
-	vm.StringOutput = true
+	vm.Importer(engine.importer.WithJPaths(jpaths))
+	// Paths are relative to the repository, which contains the GitHub directory (the gflows context may be elsewhere)
+	repoDir := filepath.Dir(filepath.Clean(engine.context.GitHubDir))
+	for _, function := range jsonnet.NativeFunctions(engine.fs, repoDir, engine.addNativeSource) {
+		vm.NativeFunction(function)
+	}
 	return vm, nil
 }
+
+func (engine *JsonnetTemplateEngine) addNativeSource(path string) {
+	engine.nativeSources[path] = true
+}
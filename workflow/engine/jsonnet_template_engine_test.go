@@ -26,7 +26,7 @@ func newJsonnetTemplateEngine(config string, roundTripper http.RoundTripper) (*c
 	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
 	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
-	templateEngine := NewJsonnetTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env)
+	templateEngine := NewJsonnetTemplateEngine(container.FileSystem(), context, container.ContentReader(), container.ContentWriter(), env)
 	return container, context, templateEngine
 }
 
@@ -77,7 +77,7 @@ func TestGetJsonnetWorkflowDefinitionsWithLibs(t *testing.T) {
 		Source:      filepath.Join(lib.LocalDir, "workflows/lib-workflow.jsonnet"),
 		Description: "my-lib/workflows/lib-workflow.jsonnet",
 		Destination: ".github/workflows/lib-workflow.yml",
-		Content:     "# File generated by gflows, do not modify\n# Source: my-lib/workflows/lib-workflow.jsonnet\n{}\n",
+		Content:     fixtures.GeneratedWorkflow("my-lib/workflows/lib-workflow.jsonnet", "{}\n"),
 		Status:      workflow.ValidationResult{Valid: true},
 		JSON:        make(map[string]interface{}),
 	}
@@ -92,13 +92,13 @@ func TestSerializationError(t *testing.T) {
 	definitions, _ := templateEngine.GetWorkflowDefinitions()
 
 	expectedError := strings.Join([]string{
-		"RUNTIME ERROR: expected string result, got: object",
-		"\tDuring manifestation\t",
+		"expected string result, got: object",
 		"You probably need to serialize the output to YAML. See https://github.com/jbrunton/gflows/wiki/Templates#serialization",
 	}, "\n")
 	expectedDefinition := workflow.Definition{
 		Name:        "test",
 		Source:      ".gflows/workflows/test.jsonnet",
+		Description: ".gflows/workflows/test.jsonnet",
 		Destination: ".github/workflows/test.yml",
 		Content:     "",
 		Status: workflow.ValidationResult{
@@ -110,6 +110,57 @@ func TestSerializationError(t *testing.T) {
 	assert.Equal(t, []*workflow.Definition{&expectedDefinition}, definitions)
 }
 
+func TestGetJsonnetMultiOutputWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	template := `local deploy(env) = std.manifestYamlDoc({ name: 'deploy-' + env });
+	{
+		['deploy-' + env + '.yml']: deploy(env)
+		for env in ['staging', 'production']
+	}`
+	fs.WriteFile(".gflows/workflows/deploy.jsonnet", []byte(template), 0644)
+	fs.WriteFile(".gflows/workflows/invalid.jsonnet", []byte(`{ 'foo/bar.yml': std.manifestYamlDoc({}) }`), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 3)
+
+	assert.Equal(t, "deploy-production", definitions[0].Name)
+	assert.Equal(t, ".gflows/workflows/deploy.jsonnet", definitions[0].Source)
+	assert.Equal(t, ".github/workflows/deploy-production.yml", definitions[0].Destination)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/deploy.jsonnet", "\"name\": \"deploy-production\"\n"), definitions[0].Content)
+
+	assert.Equal(t, "deploy-staging", definitions[1].Name)
+	assert.Equal(t, ".github/workflows/deploy-staging.yml", definitions[1].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/deploy.jsonnet", "\"name\": \"deploy-staging\"\n"), definitions[1].Content)
+
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{`invalid file name "foo/bar.yml", expected a file name without a directory`},
+	}, definitions[2].Status)
+}
+
+func TestGetJsonnetMultiOutputWorkflowDefinitionsWithDuplicates(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/deploy.jsonnet", []byte(`{ 'deploy-staging.yml': std.manifestYamlDoc({}) }`), 0644)
+	fs.WriteFile(".gflows/workflows/deploy-staging.jsonnet", []byte(`std.manifestYamlDoc({})`), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	for _, definition := range definitions {
+		assert.Equal(t, workflow.ValidationResult{
+			Valid: false,
+			Errors: []string{".github/workflows/deploy-staging.yml is generated by more than one template " +
+				"(.gflows/workflows/deploy-staging.jsonnet, .gflows/workflows/deploy.jsonnet)"},
+		}, definition.Status)
+	}
+}
+
 func TestGetJsonnetObservableSources(t *testing.T) {
 	config := strings.Join([]string{
 		"templates:",
@@ -141,6 +192,46 @@ func TestGetJsonnetObservableSources(t *testing.T) {
 	}, sources)
 }
 
+func TestGetJsonnetObservableSourcesWithNativeFunctions(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	template := `std.manifestYamlDoc({
+		name: std.native('sha256File')('go.sum'),
+		envs: std.native('listDir')('envs'),
+	})`
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(template), 0644)
+	fs.WriteFile("go.sum", []byte("hello"), 0644)
+	fs.WriteFile("envs/staging.json", []byte("{}"), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		".gflows/workflows/test.jsonnet",
+		"envs",
+		"go.sum",
+	}, sources)
+}
+
+func TestGetJsonnetObservableSourcesWithNativeFunctionsAndGitHubDir(t *testing.T) {
+	container, _, templateEngine := newJsonnetTemplateEngine("templates:\n  engine: jsonnet\ngithubDir: repo/.github", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte("std.manifestYamlDoc({ name: std.native('sha256File')('go.sum') })"), 0644)
+	fs.WriteFile("repo/go.sum", []byte("hello"), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gflows/workflows/test.jsonnet", "repo/go.sum"}, sources)
+
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte("std.manifestYamlDoc({ name: 'test' })"), 0644)
+
+	sources, err = templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gflows/workflows/test.jsonnet"}, sources)
+}
+
 func TestGetJsonnetWorkflowTemplates(t *testing.T) {
 	container, _, templateEngine := newJsonnetTemplateEngine("", fixtures.NewMockRoundTripper())
 	fs := container.FileSystem()
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+)
+
+// MultiTemplateEngine - aggregates the workflows of several engines. The engine for a workflow is given by its
+// override, if there is one, and otherwise by whichever engine recognises its template.
+type MultiTemplateEngine struct {
+	context     *config.GFlowsContext
+	engineNames []string
+	engines     map[string]workflow.TemplateEngine
+}
+
+func NewMultiTemplateEngine(context *config.GFlowsContext, engineNames []string, engines map[string]workflow.TemplateEngine) *MultiTemplateEngine {
+	return &MultiTemplateEngine{
+		context:     context,
+		engineNames: engineNames,
+		engines:     engines,
+	}
+}
+
+func (engine *MultiTemplateEngine) GetObservableSources() ([]string, error) {
+	files := []string{}
+	found := make(map[string]bool)
+	for _, engineName := range engine.engineNames {
+		sources, err := engine.engines[engineName].GetObservableSources()
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources {
+			if !found[source] {
+				found[source] = true
+				files = append(files, source)
+			}
+		}
+	}
+	return files, nil
+}
+
+// GetWorkflowDefinitions - get workflow definitions from each engine. If more than one engine defines a workflow
+// without an override to choose between them, then the definition is invalid.
+func (engine *MultiTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	definitions := []*workflow.Definition{}
+	definitionEngines := make(map[string][]string)
+	for _, engineName := range engine.engineNames {
+		engineDefinitions, err := engine.engines[engineName].GetWorkflowDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		for _, definition := range engineDefinitions {
+			override := engine.context.Config.Templates.Overrides[definition.Name]
+			if override != nil && override.Engine != "" && override.Engine != engineName {
+				continue
+			}
+			if _, exists := definitionEngines[definition.Name]; !exists {
+				definitions = append(definitions, definition)
+			}
+			definitionEngines[definition.Name] = append(definitionEngines[definition.Name], engineName)
+		}
+	}
+
+	for _, definition := range definitions {
+		engineNames := definitionEngines[definition.Name]
+		if len(engineNames) > 1 {
+			definition.Status = workflow.ValidationResult{
+				Valid: false,
+				Errors: []string{fmt.Sprintf(
+					"workflow is defined by more than one engine (%s), set templates.overrides.%s.engine to choose one",
+					strings.Join(engineNames, ", "), definition.Name)},
+			}
+		}
+	}
+
+	return definitions, nil
+}
+
+// ImportWorkflow - imports the workflow using the engine given by its override, or else the default engine
+func (engine *MultiTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
+	_, filename := filepath.Split(wf.Path)
+	workflowName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return engine.engines[engine.context.Config.GetTemplateEngine(workflowName)].ImportWorkflow(wf)
+}
+
+// GetImportedTemplatePath - returns the path given by the engine the workflow would be imported with
+func (engine *MultiTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
+	_, filename := filepath.Split(wf.Path)
+	workflowName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return engine.engines[engine.context.Config.GetTemplateEngine(workflowName)].GetImportedTemplatePath(wf)
+}
+
+// ImportCommonElements - writes the common elements to a lib for the default engine
+func (engine *MultiTemplateEngine) ImportCommonElements(common *workflow.CommonElements) (string, error) {
+	engineName := engine.context.Config.Templates.Engine
+	importer, ok := engine.engines[engineName].(workflow.CommonImporter)
+	if !ok {
+		return "", fmt.Errorf("the %s engine doesn't support extracting common steps and jobs", engineName)
+	}
+	return importer.ImportCommonElements(common)
+}
+
+// ImportWorkflowWithCommonElements - imports the workflow with references to the common elements if it uses the
+// default engine (since the common lib is only available to the default engine), or else imports it as normal
+func (engine *MultiTemplateEngine) ImportWorkflowWithCommonElements(wf *workflow.GitHubWorkflow, common *workflow.CommonElements) (string, error) {
+	_, filename := filepath.Split(wf.Path)
+	workflowName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	engineName := engine.context.Config.GetTemplateEngine(workflowName)
+	if importer, ok := engine.engines[engineName].(workflow.CommonImporter); ok && engineName == engine.context.Config.Templates.Engine {
+		return importer.ImportWorkflowWithCommonElements(wf, common)
+	}
+	return engine.engines[engineName].ImportWorkflow(wf)
+}
+
+// WorkflowGenerator - returns the generator for the default engine
+func (engine *MultiTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
+	return engine.engines[engine.context.Config.Templates.Engine].WorkflowGenerator(templateVars)
+}
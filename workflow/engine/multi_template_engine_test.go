@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMultiTemplateEngine(config string) (*content.Container, *config.GFlowsContext, *MultiTemplateEngine) {
+	ioContainer, context, _ := fixtures.NewTestContext(config)
+	container := content.NewContainer(ioContainer, &http.Client{Transport: fixtures.NewMockRoundTripper()})
+	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
+	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
+	engines := map[string]workflow.TemplateEngine{
+		"jsonnet": NewJsonnetTemplateEngine(container.FileSystem(), context, container.ContentReader(), container.ContentWriter(), env.ForEngine("jsonnet")),
+		"yaml":    NewYamlTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env.ForEngine("yaml")),
+	}
+	templateEngine := NewMultiTemplateEngine(context, context.Config.GetTemplateEngines(), engines)
+	return container, context, templateEngine
+}
+
+func TestGetMultiWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine := newMultiTemplateEngine(strings.Join([]string{
+		"templates:",
+		"  engine: jsonnet",
+		"  engines:",
+		"    yaml: {}",
+		"  overrides:",
+		"    chosen:",
+		"      engine: yaml",
+	}, "\n"))
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/other.yml", []byte("name: other\n"), 0644)
+	fs.WriteFile(".gflows/workflows/chosen.jsonnet", []byte("std.manifestYamlDoc({name: 'jsonnet'})"), 0644)
+	fs.WriteFile(".gflows/workflows/chosen.yml", []byte("name: yaml\n"), 0644)
+	fs.WriteFile(".gflows/workflows/both.jsonnet", []byte("std.manifestYamlDoc({})"), 0644)
+	fs.WriteFile(".gflows/workflows/both.yml", []byte("name: both\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 4)
+	assert.Equal(t, ".gflows/workflows/both.jsonnet", definitions[0].Source)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{"workflow is defined by more than one engine (jsonnet, yaml), set templates.overrides.both.engine to choose one"},
+	}, definitions[0].Status)
+	assert.Equal(t, ".gflows/workflows/test.jsonnet", definitions[1].Source)
+	assert.Equal(t, fixtures.ExampleWorkflow("test.jsonnet"), definitions[1].Content)
+	assert.Equal(t, ".gflows/workflows/chosen.yml", definitions[2].Source)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/chosen.yml", "name: yaml\n"), definitions[2].Content)
+	assert.Equal(t, ".gflows/workflows/other.yml", definitions[3].Source)
+	assert.True(t, definitions[3].Status.Valid)
+}
+
+func TestGetMultiObservableSources(t *testing.T) {
+	container, _, templateEngine := newMultiTemplateEngine("templates:\n  engine: jsonnet\n  engines:\n    yaml: {}")
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.jsonnet", []byte(fixtures.ExampleJsonnetTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/other.yml", []byte("name: other\n"), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gflows/workflows/test.jsonnet", ".gflows/workflows/other.yml"}, sources)
+}
+
+func TestImportMultiWorkflow(t *testing.T) {
+	container, _, templateEngine := newMultiTemplateEngine(strings.Join([]string{
+		"templates:",
+		"  engine: jsonnet",
+		"  overrides:",
+		"    other:",
+		"      engine: yaml",
+	}, "\n"))
+	fs := container.FileSystem()
+	fs.WriteFile(".github/workflows/test.yml", []byte("name: test\n"), 0644)
+	fs.WriteFile(".github/workflows/other.yml", []byte("name: other\n"), 0644)
+
+	testPath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/test.yml"})
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/test.jsonnet", testPath)
+
+	otherPath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/other.yml"})
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/other.yml", otherPath)
+}
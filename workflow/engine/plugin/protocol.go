@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// GenerateAction - requests the workflow generated from the given sources
+	GenerateAction = "generate"
+
+	// ImportAction - requests a template for the given workflow content
+	ImportAction = "import"
+)
+
+// Request - the request written as JSON to the plugin's stdin
+type Request struct {
+	Action       string            `json:"action"`
+	WorkflowName string            `json:"workflowName"`
+	SourcePaths  []string          `json:"sourcePaths,omitempty"`
+	LibPaths     []string          `json:"libPaths,omitempty"`
+	Variables    map[string]string `json:"variables,omitempty"`
+	Content      string            `json:"content,omitempty"`
+}
+
+// Response - the response read as JSON from the plugin's stdout. Content is the generated workflow for a generate
+// request, or the template for an import request.
+type Response struct {
+	Content string   `json:"content"`
+	Errors  []string `json:"errors"`
+}
+
+// Executor - sends requests to a plugin
+type Executor interface {
+	Execute(request *Request) (*Response, error)
+}
+
+// CommandExecutor - runs a plugin executable for each request
+type CommandExecutor struct {
+	Command string
+	Args    []string
+}
+
+func NewCommandExecutor(command string, args []string) *CommandExecutor {
+	return &CommandExecutor{
+		Command: command,
+		Args:    args,
+	}
+}
+
+func (executor *CommandExecutor) Execute(request *Request) (*Response, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(executor.Command, executor.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return nil, fmt.Errorf("error running plugin %s: %s", executor.Command, err)
+		}
+		return nil, fmt.Errorf("error running plugin %s: %s\n%s", executor.Command, err, message)
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("invalid response from plugin %s: %s", executor.Command, err)
+	}
+	return &response, nil
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	script := `cat > /dev/null; printf '%s' '{"content": "name: test\n", "errors": []}'`
+	executor := NewCommandExecutor("sh", []string{"-c", script})
+
+	response, err := executor.Execute(&Request{Action: GenerateAction, WorkflowName: "test"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Response{Content: "name: test\n", Errors: []string{}}, response)
+}
+
+func TestExecuteEchoesRequest(t *testing.T) {
+	script := `printf '{"content": %s}' "$(cat | sed 's/"/\\"/g; s/^/"/; s/$/"/')"`
+	executor := NewCommandExecutor("sh", []string{"-c", script})
+
+	response, err := executor.Execute(&Request{
+		Action:       GenerateAction,
+		WorkflowName: "test",
+		SourcePaths:  []string{".gflows/workflows/test.gen"},
+		LibPaths:     []string{".gflows/libs"},
+		Variables:    map[string]string{"foo": "bar"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"action":"generate","workflowName":"test","sourcePaths":[".gflows/workflows/test.gen"],"libPaths":[".gflows/libs"],"variables":{"foo":"bar"}}`, response.Content)
+}
+
+func TestExecuteFailure(t *testing.T) {
+	executor := NewCommandExecutor("sh", []string{"-c", "echo oops >&2; exit 1"})
+
+	_, err := executor.Execute(&Request{Action: GenerateAction, WorkflowName: "test"})
+
+	assert.EqualError(t, err, "error running plugin sh: exit status 1\noops")
+}
+
+func TestExecuteInvalidResponse(t *testing.T) {
+	executor := NewCommandExecutor("sh", []string{"-c", "echo not json"})
+
+	_, err := executor.Execute(&Request{Action: GenerateAction, WorkflowName: "test"})
+
+	assert.EqualError(t, err, "invalid response from plugin sh: invalid character 'o' in literal null (expecting 'u')")
+}
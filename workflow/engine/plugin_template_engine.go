@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/workflow/engine/plugin"
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/spf13/afero"
+)
+
+// PluginTemplateEngine - adapts an external plugin to the TemplateEngine interface. Each file in the workflows
+// directory with one of the configured extensions is a template, as is each directory containing such files.
+type PluginTemplateEngine struct {
+	fs            *afero.Afero
+	context       *config.GFlowsContext
+	contentWriter *content.Writer
+	env           *env.GFlowsEnv
+	engineName    string
+	config        *config.GFlowsPluginConfig
+	executor      plugin.Executor
+}
+
+func NewPluginTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, engineName string, executor plugin.Executor) *PluginTemplateEngine {
+	return &PluginTemplateEngine{
+		fs:            fs,
+		context:       context,
+		contentWriter: contentWriter,
+		env:           env,
+		engineName:    engineName,
+		config:        context.Config.GetPluginConfig(engineName),
+		executor:      executor,
+	}
+}
+
+// NewPluginExecutor - returns an executor for the command configured for the plugin. Commands given as relative
+// paths are resolved like other paths in the config.
+func NewPluginExecutor(context *config.GFlowsContext, engineName string) plugin.Executor {
+	pluginConfig := context.Config.GetPluginConfig(engineName)
+	command := pluginConfig.Command
+	if strings.ContainsRune(command, filepath.Separator) {
+		command = context.ResolvePath(command)
+	}
+	return plugin.NewCommandExecutor(command, pluginConfig.Args)
+}
+
+func (engine *PluginTemplateEngine) GetObservableSources() ([]string, error) {
+	files := []string{}
+	for _, libPath := range append(
+		engine.context.Config.GetAllLibs(),
+		engine.context.WorkflowsDir(),
+		engine.context.LibsDir(),
+	) {
+		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if libInfo.IsRemote || !libInfo.Exists {
+			// Can't watch remote or non-existent files, so continue
+			continue
+		}
+
+		if !libInfo.IsDir {
+			files = append(files, libPath)
+			continue
+		}
+
+		sources, err := engine.getSourcesInDir(libPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sources...)
+	}
+
+	return files, nil
+}
+
+// GetWorkflowDefinitions - get workflow definitions for the given context
+func (engine *PluginTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	definitions := []*workflow.Definition{}
+	for _, template := range templates {
+		workflowName := engine.getWorkflowName(template.LocalPath)
+		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+		definition := &workflow.Definition{
+			Name:        workflowName,
+			Source:      template.LocalPath,
+			Destination: destinationPath,
+			Status:      workflow.ValidationResult{Valid: true},
+		}
+
+		workflow, err := engine.apply(workflowName, template.LocalPath)
+
+		if err != nil {
+			definition.Status.Valid = false
+			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+		} else {
+			definition.SetContent(workflow, template)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+// ImportWorkflow - asks the plugin for a template for the workflow, and writes it to the workflows directory with the
+// first configured extension
+func (engine *PluginTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return "", err
+	}
+
+	_, filename := filepath.Split(wf.Path)
+	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	response, err := engine.execute(&plugin.Request{
+		Action:       plugin.ImportAction,
+		WorkflowName: templateName,
+		Variables:    engine.config.Variables,
+		Content:      workflowContent,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	templatePath := engine.GetImportedTemplatePath(wf)
+	engine.contentWriter.SafelyWriteFile(templatePath, response.Content)
+
+	return templatePath, nil
+}
+
+// GetImportedTemplatePath - imported templates are named after the workflow, with the first configured extension
+func (engine *PluginTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
+	_, filename := filepath.Split(wf.Path)
+	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	extension := ".yml"
+	if len(engine.config.Extensions) > 0 {
+		extension = engine.config.Extensions[0]
+	}
+	return filepath.Join(engine.context.WorkflowsDir(), templateName+extension)
+}
+
+// WorkflowGenerator - plugins can't be used with init, so the generator has no sources
+func (engine *PluginTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
+	return content.WorkflowGenerator{
+		Name:         "gflows",
+		TemplateVars: templateVars,
+		Sources:      []content.WorkflowSource{},
+	}
+}
+
+func (engine *PluginTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error) {
+	templates := []*pkg.PathInfo{}
+	packages, err := engine.env.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		paths, err := afero.Glob(engine.fs, filepath.Join(pkg.WorkflowsDir(), "*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			sources, err := engine.getSources(path)
+			if err != nil {
+				return nil, err
+			}
+			if len(sources) == 0 {
+				continue
+			}
+			pathInfo, err := pkg.GetPathInfo(path)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, pathInfo)
+		}
+	}
+	return templates, nil
+}
+
+func (engine *PluginTemplateEngine) getWorkflowName(path string) string {
+	name := filepath.Base(path)
+	for _, extension := range engine.config.Extensions {
+		if strings.HasSuffix(name, extension) {
+			return strings.TrimSuffix(name, extension)
+		}
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func (engine *PluginTemplateEngine) isSource(path string) bool {
+	if len(engine.config.Extensions) == 0 {
+		return true
+	}
+	for _, extension := range engine.config.Extensions {
+		if strings.HasSuffix(path, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// getSources - returns the source files for the template at the given path, which may be a file or directory
+func (engine *PluginTemplateEngine) getSources(path string) ([]string, error) {
+	isDir, err := engine.fs.IsDir(path)
+	if err != nil {
+		return nil, err
+	}
+	if isDir {
+		return engine.getSourcesInDir(path)
+	}
+	if engine.isSource(path) {
+		return []string{path}, nil
+	}
+	return []string{}, nil
+}
+
+func (engine *PluginTemplateEngine) getSourcesInDir(dir string) ([]string, error) {
+	files := []string{}
+	err := engine.fs.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !f.IsDir() && engine.isSource(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (engine *PluginTemplateEngine) apply(workflowName string, templatePath string) (string, error) {
+	sourcePaths, err := engine.getSources(templatePath)
+	if err != nil {
+		return "", err
+	}
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return "", err
+	}
+	response, err := engine.execute(&plugin.Request{
+		Action:       plugin.GenerateAction,
+		WorkflowName: workflowName,
+		SourcePaths:  sourcePaths,
+		LibPaths:     libPaths,
+		Variables:    engine.config.Variables,
+	})
+	if err != nil {
+		return "", err
+	}
+	return yamlutil.NormalizeWorkflow(response.Content)
+}
+
+func (engine *PluginTemplateEngine) execute(request *plugin.Request) (*plugin.Response, error) {
+	response, err := engine.executor.Execute(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		return nil, errors.New(strings.Join(response.Errors, "\n"))
+	}
+	if response.Content == "" {
+		return nil, fmt.Errorf("no content returned by %s", engine.engineName)
+	}
+	return response, nil
+}
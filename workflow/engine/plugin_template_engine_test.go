@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/workflow/engine/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExecutor struct {
+	requests  []*plugin.Request
+	responses map[string]*plugin.Response
+}
+
+func (executor *fakeExecutor) Execute(request *plugin.Request) (*plugin.Response, error) {
+	executor.requests = append(executor.requests, request)
+	return executor.responses[request.WorkflowName], nil
+}
+
+const examplePluginConfig = `templates:
+  engine: plugin:my-plugin
+  plugins:
+    my-plugin:
+      command: my-plugin
+      extensions: [.gen]
+      variables:
+        foo: bar`
+
+func newPluginTemplateEngine(config string, responses map[string]*plugin.Response) (*content.Container, *config.GFlowsContext, *PluginTemplateEngine, *fakeExecutor) {
+	if config == "" {
+		config = examplePluginConfig
+	}
+	ioContainer, context, _ := fixtures.NewTestContext(config)
+	container := content.NewContainer(ioContainer, &http.Client{Transport: fixtures.NewMockRoundTripper()})
+	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
+	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
+	executor := &fakeExecutor{responses: responses}
+	templateEngine := NewPluginTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env, "plugin:my-plugin", executor)
+	return container, context, templateEngine, executor
+}
+
+func TestGetPluginWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine, executor := newPluginTemplateEngine("", map[string]*plugin.Response{
+		"test":  {Content: "{name: test}"},
+		"multi": {Content: "name: multi\n"},
+	})
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.gen", []byte("test"), 0644)
+	fs.WriteFile(".gflows/workflows/multi/a.gen", []byte("a"), 0644)
+	fs.WriteFile(".gflows/workflows/multi/b.gen", []byte("b"), 0644)
+	fs.WriteFile(".gflows/workflows/other.txt", []byte("ignored"), 0644)
+	fs.WriteFile(".gflows/libs/lib.gen", []byte("lib"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, "multi", definitions[0].Name)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/multi", "name: multi\n"), definitions[0].Content)
+	assert.Equal(t, "test", definitions[1].Name)
+	assert.Equal(t, ".github/workflows/test.yml", definitions[1].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.gen", "name: test\n"), definitions[1].Content)
+	assert.Equal(t, []*plugin.Request{
+		{
+			Action:       plugin.GenerateAction,
+			WorkflowName: "multi",
+			SourcePaths:  []string{".gflows/workflows/multi/a.gen", ".gflows/workflows/multi/b.gen"},
+			LibPaths:     []string{".gflows/libs"},
+			Variables:    map[string]string{"foo": "bar"},
+		},
+		{
+			Action:       plugin.GenerateAction,
+			WorkflowName: "test",
+			SourcePaths:  []string{".gflows/workflows/test.gen"},
+			LibPaths:     []string{".gflows/libs"},
+			Variables:    map[string]string{"foo": "bar"},
+		},
+	}, executor.requests)
+}
+
+func TestGetPluginWorkflowDefinitionsWithErrors(t *testing.T) {
+	container, _, templateEngine, _ := newPluginTemplateEngine("", map[string]*plugin.Response{
+		"test":  {Errors: []string{"line 1: something went wrong", "line 2: so did this"}},
+		"empty": {},
+	})
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.gen", []byte("test"), 0644)
+	fs.WriteFile(".gflows/workflows/empty.gen", []byte("test"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{"no content returned by plugin:my-plugin"},
+	}, definitions[0].Status)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{"line 1: something went wrong\nline 2: so did this"},
+	}, definitions[1].Status)
+}
+
+func TestGetPluginObservableSources(t *testing.T) {
+	container, _, templateEngine, _ := newPluginTemplateEngine("", map[string]*plugin.Response{})
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.gen", []byte("test"), 0644)
+	fs.WriteFile(".gflows/workflows/other.txt", []byte("ignored"), 0644)
+	fs.WriteFile(".gflows/libs/lib.gen", []byte("lib"), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".gflows/workflows/test.gen", ".gflows/libs/lib.gen"}, sources)
+}
+
+func TestImportPluginWorkflow(t *testing.T) {
+	container, _, templateEngine, executor := newPluginTemplateEngine("", map[string]*plugin.Response{
+		"test": {Content: "imported"},
+	})
+	fs := container.FileSystem()
+	fs.WriteFile(".github/workflows/test.yml", []byte("name: test\n"), 0644)
+
+	templatePath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/test.yml"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/test.gen", templatePath)
+	templateContent, _ := fs.ReadFile(templatePath)
+	assert.Equal(t, "imported", string(templateContent))
+	assert.Equal(t, []*plugin.Request{
+		{
+			Action:       plugin.ImportAction,
+			WorkflowName: "test",
+			Variables:    map[string]string{"foo": "bar"},
+			Content:      "name: test\n",
+		},
+	}, executor.requests)
+}
+
+func TestNewPluginExecutor(t *testing.T) {
+	_, context, _, _ := newPluginTemplateEngine(strings.Join([]string{
+		"templates:",
+		"  engine: plugin:local",
+		"  plugins:",
+		"    local:",
+		"      command: scripts/generate.sh",
+		"      args: [--verbose]",
+		"    global:",
+		"      command: my-plugin",
+	}, "\n"), map[string]*plugin.Response{})
+
+	assert.Equal(t, plugin.NewCommandExecutor(".gflows/scripts/generate.sh", []string{"--verbose"}), NewPluginExecutor(context, "plugin:local"))
+	assert.Equal(t, plugin.NewCommandExecutor("my-plugin", nil), NewPluginExecutor(context, "plugin:global"))
+}
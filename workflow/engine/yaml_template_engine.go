@@ -0,0 +1,388 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlIncludeTag - the tag used to include files from the lib paths
+const yamlIncludeTag = "!include"
+
+// yamlExtensionPrefix - top level keys with this prefix are removed from the generated workflow
+const yamlExtensionPrefix = "x-"
+
+type YamlTemplateEngine struct {
+	fs            *afero.Afero
+	context       *config.GFlowsContext
+	contentWriter *content.Writer
+	env           *env.GFlowsEnv
+}
+
+func NewYamlTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv) *YamlTemplateEngine {
+	return &YamlTemplateEngine{
+		fs:            fs,
+		context:       context,
+		contentWriter: contentWriter,
+		env:           env,
+	}
+}
+
+func (engine *YamlTemplateEngine) GetObservableSources() ([]string, error) {
+	files := []string{}
+	for _, libPath := range append(
+		engine.context.Config.GetAllLibs(),
+		engine.context.WorkflowsDir(),
+		engine.context.LibsDir(),
+	) {
+		libInfo, err := pkg.GetLibInfo(libPath, engine.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if libInfo.IsRemote || !libInfo.Exists {
+			// Can't watch remote or non-existent files, so continue
+			continue
+		}
+
+		if !libInfo.IsDir {
+			files = append(files, libPath)
+			continue
+		}
+
+		sources, err := engine.getSourcesInDir(libPath)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sources...)
+	}
+
+	return files, nil
+}
+
+// GetWorkflowDefinitions - get workflow definitions for the given context
+func (engine *YamlTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definition, error) {
+	templates, err := engine.getWorkflowTemplates()
+	if err != nil {
+		return nil, err
+	}
+	definitions := []*workflow.Definition{}
+	for _, template := range templates {
+		workflowName := engine.getWorkflowName(template.LocalPath)
+		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
+		definition := &workflow.Definition{
+			Name:        workflowName,
+			Source:      template.LocalPath,
+			Destination: destinationPath,
+			Status:      workflow.ValidationResult{Valid: true},
+		}
+
+		workflow, err := engine.apply(workflowName, template.LocalPath)
+
+		if err != nil {
+			definition.Status.Valid = false
+			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
+		} else {
+			definition.SetContent(workflow, template)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+func (engine *YamlTemplateEngine) ImportWorkflow(wf *workflow.GitHubWorkflow) (string, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return "", err
+	}
+
+	templateContent, err := yamlutil.NormalizeWorkflow(workflowContent)
+	if err != nil {
+		return "", err
+	}
+
+	templatePath := engine.GetImportedTemplatePath(wf)
+	engine.contentWriter.SafelyWriteFile(templatePath, templateContent)
+
+	return templatePath, nil
+}
+
+func (engine *YamlTemplateEngine) GetImportedTemplatePath(wf *workflow.GitHubWorkflow) string {
+	_, filename := filepath.Split(wf.Path)
+	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return filepath.Join(engine.context.WorkflowsDir(), templateName+".yml")
+}
+
+func (engine *YamlTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
+	return content.WorkflowGenerator{
+		Name:         "gflows",
+		TemplateVars: templateVars,
+		Sources: []content.WorkflowSource{
+			content.NewWorkflowSource("/yaml/libs/setup-go.yml", "/libs/setup-go.yml"),
+			content.NewWorkflowSource("/yaml/workflows/gflows.yml", "/workflows/$WORKFLOW_NAME.yml"),
+			content.NewWorkflowSource("/yaml/config.yml", "/config.yml"),
+		},
+	}
+}
+
+func (engine *YamlTemplateEngine) getWorkflowTemplates() ([]*pkg.PathInfo, error) {
+	templates := []*pkg.PathInfo{}
+	packages, err := engine.env.GetPackages()
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range packages {
+		paths, err := engine.getYamlFiles(pkg.WorkflowsDir())
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			pathInfo, err := pkg.GetPathInfo(path)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, pathInfo)
+		}
+	}
+	return templates, nil
+}
+
+func (engine *YamlTemplateEngine) getYamlFiles(dir string) ([]string, error) {
+	files := []string{}
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		paths, err := afero.Glob(engine.fs, filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, paths...)
+	}
+	return files, nil
+}
+
+func (engine *YamlTemplateEngine) getWorkflowName(filename string) string {
+	_, templateFileName := filepath.Split(filename)
+	return strings.TrimSuffix(templateFileName, filepath.Ext(templateFileName))
+}
+
+func (engine *YamlTemplateEngine) getSourcesInDir(dir string) ([]string, error) {
+	files := []string{}
+	err := engine.fs.Walk(dir, func(path string, f os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if !f.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// apply - resolves any includes in the template, and then expands anchors, aliases and merge keys to give the
+// flattened workflow
+func (engine *YamlTemplateEngine) apply(workflowName string, templatePath string) (string, error) {
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := engine.load(templatePath, libPaths, []string{})
+	if err != nil {
+		return "", err
+	}
+	node, err = expandAliases(node, nil)
+	if err != nil {
+		return "", fmt.Errorf("%s: %s", templatePath, err)
+	}
+	removeExtensionKeys(node)
+
+	workflowContent, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return yamlutil.NormalizeWorkflow(string(workflowContent))
+}
+
+// load - parses the YAML file at the given path and replaces any included nodes. includeStack is used to detect
+// cycles.
+func (engine *YamlTemplateEngine) load(path string, libPaths []string, includeStack []string) (*yaml.Node, error) {
+	for _, includedPath := range includeStack {
+		if includedPath == path {
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(includeStack, path), " -> "))
+		}
+	}
+
+	source, err := engine.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(source, &document); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+
+	root := document.Content[0]
+	err = engine.resolveIncludes(root, path, libPaths, append(includeStack, path))
+	return root, err
+}
+
+func (engine *YamlTemplateEngine) resolveIncludes(node *yaml.Node, path string, libPaths []string, includeStack []string) error {
+	if node.Tag == yamlIncludeTag {
+		if node.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s:%d: %s expects a file name", path, node.Line, yamlIncludeTag)
+		}
+		includePath, err := engine.findInclude(node.Value, libPaths)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s", path, node.Line, err)
+		}
+		included, err := engine.load(includePath, libPaths, includeStack)
+		if err != nil {
+			return err
+		}
+		anchor := node.Anchor
+		*node = *included
+		if anchor != "" {
+			node.Anchor = anchor
+		}
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := engine.resolveIncludes(child, path, libPaths, includeStack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findInclude - returns the first file in the lib paths matching the given name
+func (engine *YamlTemplateEngine) findInclude(name string, libPaths []string) (string, error) {
+	for _, libPath := range libPaths {
+		candidates := []string{filepath.Join(libPath, name)}
+		if filepath.Base(libPath) == name {
+			// the lib path may be a file
+			candidates = append(candidates, libPath)
+		}
+		for _, candidate := range candidates {
+			isDir, err := engine.fs.IsDir(candidate)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return "", err
+			}
+			if !isDir {
+				return candidate, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("could not find %s in lib paths", name)
+}
+
+// expandAliases - returns a copy of the node with aliases replaced by the nodes they refer to, merge keys applied and
+// anchors removed. anchorStack holds the anchored nodes currently being expanded, and is used to detect cycles.
+func expandAliases(node *yaml.Node, anchorStack []*yaml.Node) (*yaml.Node, error) {
+	if node.Kind == yaml.AliasNode {
+		for _, anchoredNode := range anchorStack {
+			if anchoredNode == node.Alias {
+				return nil, fmt.Errorf("line %d: alias cycle detected: *%s refers to a node which contains it", node.Line, node.Value)
+			}
+		}
+		return expandAliases(node.Alias, anchorStack)
+	}
+	if node.Anchor != "" {
+		anchorStack = append(anchorStack, node)
+	}
+
+	result := *node
+	result.Anchor = ""
+	result.Content = []*yaml.Node{}
+	if node.Kind != yaml.MappingNode {
+		for _, child := range node.Content {
+			expandedChild, err := expandAliases(child, anchorStack)
+			if err != nil {
+				return nil, err
+			}
+			result.Content = append(result.Content, expandedChild)
+		}
+		return &result, nil
+	}
+
+	// Explicit keys take precedence over merged keys, as do keys merged earlier
+	keys := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isMergeKey(node.Content[i]) {
+			keys[node.Content[i].Value] = true
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		expandedValue, err := expandAliases(value, anchorStack)
+		if err != nil {
+			return nil, err
+		}
+		if !isMergeKey(key) {
+			expandedKey, err := expandAliases(key, anchorStack)
+			if err != nil {
+				return nil, err
+			}
+			result.Content = append(result.Content, expandedKey, expandedValue)
+			continue
+		}
+
+		sources := []*yaml.Node{expandedValue}
+		if expandedValue.Kind == yaml.SequenceNode {
+			sources = expandedValue.Content
+		}
+		for _, source := range sources {
+			if source.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("line %d: merge key expects a mapping or a sequence of mappings", key.Line)
+			}
+			for j := 0; j+1 < len(source.Content); j += 2 {
+				mergedKey := source.Content[j]
+				if keys[mergedKey.Value] {
+					continue
+				}
+				keys[mergedKey.Value] = true
+				result.Content = append(result.Content, mergedKey, source.Content[j+1])
+			}
+		}
+	}
+	return &result, nil
+}
+
+func isMergeKey(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!merge"
+}
+
+// removeExtensionKeys - removes top level keys prefixed with yamlExtensionPrefix, which may be used to declare
+// anchors
+func removeExtensionKeys(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := []*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind == yaml.ScalarNode && strings.HasPrefix(key.Value, yamlExtensionPrefix) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	node.Content = content
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/env"
+	"github.com/jbrunton/gflows/fixtures"
+	"github.com/jbrunton/gflows/io/content"
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+const exampleYamlTemplate = `x-defaults: &defaults
+  runs-on: ubuntu-latest
+  env:
+    FOO: bar
+on:
+  push:
+    branches: [develop]
+jobs:
+  test:
+    <<: *defaults
+    env:
+      FOO: baz
+    steps: !include steps.yml
+  build:
+    <<: *defaults
+    steps: !include steps.yml
+`
+
+const exampleYamlLib = `- &checkout
+  uses: actions/checkout@v2
+- run: echo hello, world!
+`
+
+const exampleYamlWorkflow = `"on":
+  push:
+    branches:
+    - develop
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    env:
+      FOO: baz
+    steps:
+    - uses: actions/checkout@v2
+    - run: echo hello, world!
+  build:
+    runs-on: ubuntu-latest
+    env:
+      FOO: bar
+    steps:
+    - uses: actions/checkout@v2
+    - run: echo hello, world!
+`
+
+func newYamlTemplateEngine(config string, roundTripper http.RoundTripper) (*content.Container, *config.GFlowsContext, *YamlTemplateEngine) {
+	if config == "" {
+		config = "templates:\n  engine: yaml"
+	}
+	ioContainer, context, _ := fixtures.NewTestContext(config)
+	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
+	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
+	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
+	templateEngine := NewYamlTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env)
+	return container, context, templateEngine
+}
+
+func TestGetYamlWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine := newYamlTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml", []byte(exampleYamlTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.yml", []byte(exampleYamlLib), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, "test", definitions[0].Name)
+	assert.Equal(t, ".github/workflows/test.yml", definitions[0].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml", exampleYamlWorkflow), definitions[0].Content)
+}
+
+func TestGetYamlWorkflowDefinitionsWithPackages(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: yaml",
+		"  defaults:",
+		"    dependencies:",
+		"    - /path/to/my-lib",
+	}, "\n")
+	container, _, templateEngine := newYamlTemplateEngine(config, fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml", []byte(exampleYamlTemplate), 0644)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/gflowspkg.json", `{"files": ["libs/steps.yml", "workflows/lib-workflow.yml"]}`)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/libs/steps.yml", exampleYamlLib)
+	container.ContentWriter().SafelyWriteFile("/path/to/my-lib/workflows/lib-workflow.yml", "name: lib\n")
+	lib, _ := templateEngine.env.LoadDependency("/path/to/my-lib")
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 2)
+	assert.Equal(t, filepath.Join(lib.LocalDir, "workflows/lib-workflow.yml"), definitions[0].Source)
+	assert.Equal(t, fixtures.GeneratedWorkflow("my-lib/workflows/lib-workflow.yml", "name: lib\n"), definitions[0].Content)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml", exampleYamlWorkflow), definitions[1].Content)
+}
+
+func TestGetYamlWorkflowDefinitionsWithErrors(t *testing.T) {
+	container, _, templateEngine := newYamlTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/missing.yml", []byte("jobs:\n  test:\n    steps: !include missing.yml\n"), 0644)
+	fs.WriteFile(".gflows/workflows/cycle.yml", []byte("jobs: !include cycle.yml\n"), 0644)
+	fs.WriteFile(".gflows/libs/cycle.yml", []byte("test: !include cycle.yml\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{"include cycle detected: .gflows/workflows/cycle.yml -> .gflows/libs/cycle.yml -> .gflows/libs/cycle.yml"},
+	}, definitions[0].Status)
+	assert.Equal(t, workflow.ValidationResult{
+		Valid:  false,
+		Errors: []string{".gflows/workflows/missing.yml:3: could not find missing.yml in lib paths"},
+	}, definitions[1].Status)
+}
+
+func TestExpandAliasesWithCycle(t *testing.T) {
+	var node yaml.Node
+	err := yaml.Unmarshal([]byte("a: &x\n  runs-on: ubuntu-latest\n  nested: *x\n"), &node)
+	assert.NoError(t, err)
+
+	_, err = expandAliases(&node, nil)
+
+	assert.EqualError(t, err, "line 3: alias cycle detected: *x refers to a node which contains it")
+}
+
+func TestGetYamlObservableSources(t *testing.T) {
+	container, _, templateEngine := newYamlTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/test.yml", []byte(exampleYamlTemplate), 0644)
+	fs.WriteFile(".gflows/workflows/invalid.ext", []byte(exampleYamlTemplate), 0644)
+	fs.WriteFile(".gflows/libs/steps.yml", []byte(exampleYamlLib), 0644)
+
+	sources, err := templateEngine.GetObservableSources()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		".gflows/workflows/test.yml",
+		".gflows/libs/steps.yml",
+	}, sources)
+}
+
+func TestImportYamlWorkflow(t *testing.T) {
+	container, _, templateEngine := newYamlTemplateEngine("", fixtures.NewMockRoundTripper())
+	fs := container.FileSystem()
+	fs.WriteFile(".github/workflows/test.yml", []byte(exampleYamlWorkflow), 0644)
+
+	templatePath, err := templateEngine.ImportWorkflow(&workflow.GitHubWorkflow{Path: ".github/workflows/test.yml"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ".gflows/workflows/test.yml", templatePath)
+	definitions, _ := templateEngine.GetWorkflowDefinitions()
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test.yml", exampleYamlWorkflow), definitions[0].Content)
+}
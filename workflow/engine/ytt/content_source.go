@@ -0,0 +1,27 @@
+package ytt
+
+import (
+	"fmt"
+
+	"github.com/jbrunton/gflows/io/content"
+)
+
+// ContentSource - a source for files which may be local or remote
+type ContentSource struct {
+	reader       *content.Reader
+	path         string
+	relativePath string
+}
+
+func NewContentSource(reader *content.Reader, path, relativePath string) ContentSource {
+	return ContentSource{reader, path, relativePath}
+}
+
+func (s ContentSource) Description() string { return fmt.Sprintf("file '%s'", s.path) }
+
+func (s ContentSource) RelativePath() (string, error) { return s.relativePath, nil }
+
+func (s ContentSource) Bytes() ([]byte, error) {
+	data, err := s.reader.ReadContent(s.path)
+	return []byte(data), err
+}
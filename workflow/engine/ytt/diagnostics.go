@@ -0,0 +1,82 @@
+package ytt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jbrunton/gflows/workflow"
+)
+
+var (
+	// frameRegex - matches locations in ytt errors, e.g. "    deploy.yml:6 |     environment: #@ foo.bar"
+	frameRegex = regexp.MustCompile(`^\s*(\S+):(\d+) \| (.*)$`)
+
+	// functionRegex - matches the function names which precede locations in ytt stack traces, e.g. "    in f"
+	functionRegex = regexp.MustCompile(`^\s*in (\S+)$`)
+
+	// yamlErrorRegex - matches errors parsing YAML templates
+	yamlErrorRegex = regexp.MustCompile(`^Unmarshaling YAML template '([^']+)': yaml: line (\d+): (.*)$`)
+)
+
+// GetDiagnostics - parses the errors in the output of ytt. Each error is given on a line starting with "- ", followed
+// by the locations of the calls which led to it. resolvePath is used to map file names relative to the ytt library
+// to source paths, and readLine to read source code for errors which don't include it. Returns nil if the output
+// couldn't be parsed.
+func GetDiagnostics(output string, resolvePath func(file string) string, readLine func(path string, line int) string) []workflow.Diagnostic {
+	lines := strings.Split(strings.Trim(output, " \n\r"), "\n")
+	if match := yamlErrorRegex.FindStringSubmatch(lines[0]); match != nil && len(lines) == 1 {
+		path := resolvePath(match[1])
+		line, _ := strconv.Atoi(match[2])
+		return []workflow.Diagnostic{{
+			File:    path,
+			Line:    line,
+			Message: match[3],
+			Snippet: readLine(path, line),
+		}}
+	}
+
+	diagnostics := []workflow.Diagnostic{}
+	var diagnostic *workflow.Diagnostic
+	function := ""
+	for _, line := range lines {
+		if strings.HasPrefix(line, "- ") {
+			diagnostics = append(diagnostics, workflow.Diagnostic{Message: strings.TrimPrefix(line, "- ")})
+			diagnostic = &diagnostics[len(diagnostics)-1]
+			function = ""
+			continue
+		}
+		if diagnostic == nil {
+			return nil
+		}
+		if match := functionRegex.FindStringSubmatch(line); match != nil {
+			function = match[1]
+			continue
+		}
+		if match := frameRegex.FindStringSubmatch(line); match != nil {
+			path := resolvePath(match[1])
+			lineNumber, _ := strconv.Atoi(match[2])
+			if diagnostic.File == "" {
+				diagnostic.File = path
+				diagnostic.Line = lineNumber
+				diagnostic.Snippet = match[3]
+			}
+			frame := fmt.Sprintf("%s:%d", path, lineNumber)
+			if function != "" {
+				frame = fmt.Sprintf("%s in %s", frame, function)
+			}
+			diagnostic.Stack = append(diagnostic.Stack, frame)
+			function = ""
+			continue
+		}
+		if strings.TrimSpace(line) != "" && diagnostic.File == "" {
+			diagnostic.Message = diagnostic.Message + "\n" + strings.TrimSpace(line)
+		}
+	}
+
+	if len(diagnostics) == 0 {
+		return nil
+	}
+	return diagnostics
+}
@@ -0,0 +1,71 @@
+package ytt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func resolvePath(file string) string {
+	return "workflows/test/" + file
+}
+
+func readLine(path string, line int) string {
+	return "jobs: [foo"
+}
+
+func TestGetDiagnostics(t *testing.T) {
+	output := strings.Join([]string{
+		"",
+		"- struct has no .nope field or method",
+		"    in runner",
+		"      config.yml:3 | #@   return values.nope",
+		"    in <toplevel>",
+		"      config.yml:8 |     runs-on: #@ runner(data.values)",
+		"",
+		"- undefined: foo",
+		"    config.yml:10 |     environment: #@ foo",
+		"",
+	}, "\n")
+
+	diagnostics := GetDiagnostics(output, resolvePath, readLine)
+
+	assert.Equal(t, []workflow.Diagnostic{
+		{
+			File:    "workflows/test/config.yml",
+			Line:    3,
+			Message: "struct has no .nope field or method",
+			Snippet: "#@   return values.nope",
+			Stack: []string{
+				"workflows/test/config.yml:3 in runner",
+				"workflows/test/config.yml:8 in <toplevel>",
+			},
+		},
+		{
+			File:    "workflows/test/config.yml",
+			Line:    10,
+			Message: "undefined: foo",
+			Snippet: "    environment: #@ foo",
+			Stack:   []string{"workflows/test/config.yml:10"},
+		},
+	}, diagnostics)
+}
+
+func TestGetYamlDiagnostics(t *testing.T) {
+	output := "Unmarshaling YAML template 'config.yml': yaml: line 2: did not find expected ',' or ']'"
+
+	diagnostics := GetDiagnostics(output, resolvePath, readLine)
+
+	assert.Equal(t, []workflow.Diagnostic{{
+		File:    "workflows/test/config.yml",
+		Line:    2,
+		Message: "did not find expected ',' or ']'",
+		Snippet: "jobs: [foo",
+	}}, diagnostics)
+}
+
+func TestGetDiagnosticsUnknownFormat(t *testing.T) {
+	assert.Nil(t, GetDiagnostics("Non-string key at top level: 123", resolvePath, readLine))
+}
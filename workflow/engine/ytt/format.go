@@ -0,0 +1,364 @@
+package ytt
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/k14s/ytt/pkg/filepos"
+	"github.com/k14s/ytt/pkg/yamlmeta"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputDocument - a document printed by ytt, and the file it was printed to
+type OutputDocument struct {
+	Document *yamlmeta.Document
+	File     string
+	// Last - true if it's the last document in the file, which is given any comments at the end of the template
+	Last bool
+}
+
+// GetOutputDocuments - returns the documents which ytt prints (i.e. those which aren't empty), in order
+func GetOutputDocuments(docSet *yamlmeta.DocumentSet) []*yamlmeta.Document {
+	documents := []*yamlmeta.Document{}
+	for _, document := range docSet.Items {
+		if !document.IsEmpty() {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+// Formatter - applies the comments and formatting of templates to the YAML printed by ytt, which otherwise drops
+// comments and uses its own quoting and styles. Comments are taken from the metadata of the evaluated nodes (ytt
+// treats plain YAML comments as comments, since unknown comments are ignored). The quoting of strings and the style of
+// flow collections are taken from the templates, for any values written in them.
+type Formatter struct {
+	readSource func(file string) ([]byte, error)
+	sources    map[string]*templateSource
+}
+
+// NewFormatter - returns a formatter which reads templates with the given function. Files are named as in ytt's
+// positions, i.e. relative to the directory they were loaded from.
+func NewFormatter(readSource func(file string) ([]byte, error)) *Formatter {
+	return &Formatter{readSource: readSource, sources: make(map[string]*templateSource)}
+}
+
+// templateSource - a template parsed as YAML, with its values indexed by line
+type templateSource struct {
+	content []byte
+	lines   []string
+	values  map[int][]*sourceValue
+}
+
+// sourceValue - a value in a template, with its key if it's in a mapping
+type sourceValue struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// Format - returns the YAML printed by ytt for the document with the comments and formatting of its templates. The
+// content is returned unchanged if there aren't any.
+func (formatter *Formatter) Format(content string, document *OutputDocument) (string, error) {
+	node, err := yamlutil.ParseWorkflowNode(content)
+	if err != nil {
+		return "", err
+	}
+	state := &formatState{formatter: formatter}
+	root := node.Content[0]
+	state.formatValue(document.Document.Value, root)
+	if document.Last {
+		state.formatFoot(document.File, node)
+	}
+	if !state.changed {
+		return content, nil
+	}
+	splitDocumentComment(document.Document.Value, node)
+	return yamlutil.EncodeNode(node)
+}
+
+type formatState struct {
+	formatter *Formatter
+	changed   bool
+}
+
+// formatValue - applies the comments and formatting for the ytt value to the YAML node. The YAML was printed from the
+// value, so they have the same structure.
+func (state *formatState) formatValue(value interface{}, node *yaml.Node) {
+	switch value := value.(type) {
+	case *yamlmeta.Map:
+		if node.Kind != yaml.MappingNode || len(node.Content) != 2*len(value.Items) {
+			return
+		}
+		if len(node.Content) > 0 {
+			// comments above the first item may be assigned to the map
+			node.Content[0].HeadComment = state.join(node.Content[0].HeadComment, getComments(value.Metas))
+		}
+		for i, item := range value.Items {
+			key, itemValue := node.Content[2*i], node.Content[2*i+1]
+			head, line := splitMetas(item.Metas, item.Position)
+			key.HeadComment = state.join(key.HeadComment, head)
+			if itemValue.Kind == yaml.ScalarNode {
+				itemValue.LineComment = state.join(itemValue.LineComment, line)
+			} else {
+				key.LineComment = state.join(key.LineComment, line)
+			}
+			if keyValue, ok := item.Key.(string); ok {
+				state.formatStyle(itemValue, item.Position, func(source *sourceValue) bool {
+					return source.key != nil && source.key.Value == keyValue
+				})
+			}
+			state.formatValue(item.Value, itemValue)
+		}
+	case *yamlmeta.Array:
+		if node.Kind != yaml.SequenceNode || len(node.Content) != len(value.Items) {
+			return
+		}
+		if len(node.Content) > 0 {
+			node.Content[0].HeadComment = state.join(node.Content[0].HeadComment, getComments(value.Metas))
+		}
+		for i, item := range value.Items {
+			head, line := splitMetas(item.Metas, item.Position)
+			node.Content[i].HeadComment = state.join(node.Content[i].HeadComment, head)
+			node.Content[i].LineComment = state.join(node.Content[i].LineComment, line)
+			state.formatStyle(node.Content[i], item.Position, func(source *sourceValue) bool {
+				return source.key == nil
+			})
+			state.formatValue(item.Value, node.Content[i])
+		}
+	}
+}
+
+// formatStyle - applies the style of the value in the template at the given position, if it's the same value
+func (state *formatState) formatStyle(node *yaml.Node, position *filepos.Position, matches func(*sourceValue) bool) {
+	source := state.formatter.getSource(position)
+	if source == nil {
+		return
+	}
+	for _, sourceValue := range source.values[position.Line()] {
+		value := sourceValue.value
+		if !matches(sourceValue) || value.Kind != node.Kind {
+			continue
+		}
+		style := node.Style
+		switch node.Kind {
+		case yaml.ScalarNode:
+			if node.Tag == "!!str" && value.Tag == "!!str" && value.Value == node.Value {
+				style = value.Style
+			}
+		case yaml.MappingNode, yaml.SequenceNode:
+			style = value.Style & yaml.FlowStyle
+		}
+		if style != node.Style {
+			node.Style = style
+			state.changed = true
+		}
+		return
+	}
+}
+
+// formatFoot - applies the comments at the end of the template to the document. They're placed after the last key
+// with the same indentation, or at the end of the document if there isn't one.
+func (state *formatState) formatFoot(file string, document *yaml.Node) {
+	source := state.formatter.getSourceFile(file)
+	if source == nil {
+		return
+	}
+	comment, indent := source.getFootComment(file)
+	if comment == "" {
+		return
+	}
+	if key := findLastKey(document.Content[0], indent); key != nil {
+		key.FootComment = state.join(key.FootComment, comment)
+	} else {
+		document.FootComment = state.join(document.FootComment, comment)
+	}
+}
+
+func (state *formatState) join(comment string, other string) string {
+	if other == "" {
+		return comment
+	}
+	state.changed = true
+	if comment == "" {
+		return other
+	}
+	return comment + "\n" + other
+}
+
+func (formatter *Formatter) getSource(position *filepos.Position) *templateSource {
+	if !position.IsKnown() {
+		return nil
+	}
+	return formatter.getSourceFile(getFile(position))
+}
+
+func (formatter *Formatter) getSourceFile(file string) *templateSource {
+	if source, ok := formatter.sources[file]; ok {
+		return source
+	}
+	var source *templateSource
+	if content, err := formatter.readSource(file); err == nil {
+		source = parseTemplateSource(content)
+	}
+	formatter.sources[file] = source
+	return source
+}
+
+func parseTemplateSource(content []byte) *templateSource {
+	source := &templateSource{
+		content: content,
+		lines:   strings.Split(string(content), "\n"),
+		values:  make(map[int][]*sourceValue),
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yaml.Node
+		if err := decoder.Decode(&document); err != nil {
+			// either the end of the file, or a template which isn't valid YAML (in which case there's nothing to apply)
+			break
+		}
+		source.index(&document)
+	}
+	return source
+}
+
+func (source *templateSource) index(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			source.values[key.Line] = append(source.values[key.Line], &sourceValue{key: key, value: node.Content[i+1]})
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			source.values[item.Line] = append(source.values[item.Line], &sourceValue{value: item})
+		}
+	}
+	for _, child := range node.Content {
+		source.index(child)
+	}
+}
+
+// getFootComment - returns the plain comments at the end of the template, and their indentation. ytt doesn't assign
+// these to any node, so they aren't kept when it's evaluated.
+func (source *templateSource) getFootComment(file string) (string, int) {
+	docSet, err := yamlmeta.NewDocumentSetFromBytes(source.content, yamlmeta.DocSetOpts{AssociatedName: file})
+	if err != nil || len(docSet.Items) == 0 {
+		return "", 0
+	}
+	last := docSet.Items[len(docSet.Items)-1]
+	if !last.IsEmpty() {
+		return "", 0
+	}
+	metas := getPlainMetas(last.Metas)
+	if len(metas) == 0 || !metas[0].Position.IsKnown() || metas[0].Position.Line() > len(source.lines) {
+		return "", 0
+	}
+	line := source.lines[metas[0].Position.Line()-1]
+	return getComments(metas), len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// findLastKey - returns the most deeply nested key at the end of the node with at most the given indentation
+func findLastKey(node *yaml.Node, indent int) *yaml.Node {
+	var result *yaml.Node
+	for node != nil && len(node.Content) > 0 {
+		switch node.Kind {
+		case yaml.MappingNode:
+			key := node.Content[len(node.Content)-2]
+			if key.Column-1 > indent {
+				return result
+			}
+			result = key
+			node = node.Content[len(node.Content)-1]
+		case yaml.SequenceNode:
+			node = node.Content[len(node.Content)-1]
+		default:
+			return result
+		}
+	}
+	return result
+}
+
+// splitMetas - returns the comments above the node and the comments at the end of its line. ytt assigns comments on
+// the same line to the last node on that line, and others to the first node on the next line.
+func splitMetas(metas []*yamlmeta.Meta, position *filepos.Position) (string, string) {
+	head, line := []*yamlmeta.Meta{}, []*yamlmeta.Meta{}
+	for _, meta := range metas {
+		if position.IsKnown() && meta.Position.IsKnown() && meta.Position.Line() == position.Line() {
+			line = append(line, meta)
+		} else {
+			head = append(head, meta)
+		}
+	}
+	return getComments(head), getComments(line)
+}
+
+// splitDocumentComment - moves comments at the start of the document which are separated from its first key by a
+// blank line to the document, as the YAML parser does
+func splitDocumentComment(value interface{}, document *yaml.Node) {
+	ytMap, ok := value.(*yamlmeta.Map)
+	root := document.Content[0]
+	if !ok || len(ytMap.Items) == 0 || root.Kind != yaml.MappingNode || root.Content[0].HeadComment == "" {
+		return
+	}
+	firstItem := ytMap.Items[0]
+	if !firstItem.Position.IsKnown() {
+		return
+	}
+	line := firstItem.Position.Line()
+	metas := []*yamlmeta.Meta{}
+	for _, meta := range getPlainMetas(append(append([]*yamlmeta.Meta{}, ytMap.Metas...), firstItem.Metas...)) {
+		if getLine(meta) < line {
+			metas = append(metas, meta)
+		}
+	}
+	sort.SliceStable(metas, func(i, j int) bool { return getLine(metas[i]) < getLine(metas[j]) })
+	// the comments directly above the first key stay with it
+	split := len(metas)
+	for split > 0 && getLine(metas[split-1]) == line-(len(metas)-split)-1 {
+		split--
+	}
+	if split == 0 {
+		return
+	}
+	document.HeadComment = getComments(metas[:split])
+	root.Content[0].HeadComment = getComments(metas[split:])
+}
+
+// getComments - returns the plain YAML comments in the metadata (i.e. those which aren't ytt comments or annotations),
+// one per line
+func getComments(metas []*yamlmeta.Meta) string {
+	lines := []string{}
+	for _, meta := range getPlainMetas(metas) {
+		lines = append(lines, "#"+meta.Data)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func getPlainMetas(metas []*yamlmeta.Meta) []*yamlmeta.Meta {
+	plainMetas := []*yamlmeta.Meta{}
+	for _, meta := range metas {
+		if !strings.HasPrefix(meta.Data, "!") && !strings.HasPrefix(meta.Data, "@") {
+			plainMetas = append(plainMetas, meta)
+		}
+	}
+	return plainMetas
+}
+
+func getLine(meta *yamlmeta.Meta) int {
+	if !meta.Position.IsKnown() {
+		return 0
+	}
+	return meta.Position.Line()
+}
+
+// getFile - returns the name of the file for the position, which is only available as a prefix of its string
+func getFile(position *filepos.Position) string {
+	compact := position.AsCompactString()
+	if index := strings.LastIndex(compact, ":"); index >= 0 {
+		return compact[:index]
+	}
+	return ""
+}
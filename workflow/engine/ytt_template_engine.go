@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -20,22 +21,26 @@ import (
 	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
 	"github.com/k14s/ytt/pkg/files"
 	"github.com/k14s/ytt/pkg/workspace"
+	"github.com/k14s/ytt/pkg/yamlmeta"
 	"github.com/spf13/afero"
 	"github.com/thoas/go-funk"
+	"gopkg.in/yaml.v3"
 )
 
 type YttTemplateEngine struct {
 	fs            *afero.Afero
 	context       *config.GFlowsContext
+	contentReader *content.Reader
 	contentWriter *content.Writer
 	env           *env.GFlowsEnv
 	logger        *io.Logger
 }
 
-func NewYttTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) *YttTemplateEngine {
+func NewYttTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, contentReader *content.Reader, contentWriter *content.Writer, env *env.GFlowsEnv, logger *io.Logger) *YttTemplateEngine {
 	return &YttTemplateEngine{
 		fs:            fs,
 		context:       context,
+		contentReader: contentReader,
 		contentWriter: contentWriter,
 		env:           env,
 		logger:        logger,
@@ -44,8 +49,10 @@ func NewYttTemplateEngine(fs *afero.Afero, context *config.GFlowsContext, conten
 
 func (engine *YttTemplateEngine) GetObservableSources() ([]string, error) {
 	files := []string{}
+	libPaths := append(engine.context.Config.GetAllEngineLibs("ytt"), engine.context.Config.GetAllDataValues()...)
+	libPaths = append(libPaths, engine.context.Config.GetAllOverlays()...)
 	for _, libPath := range append(
-		engine.context.Config.GetAllLibs(),
+		libPaths,
 		engine.context.WorkflowsDir(),
 		engine.context.LibsDir(),
 	) {
@@ -114,46 +121,157 @@ func (engine *YttTemplateEngine) GetWorkflowDefinitions() ([]*workflow.Definitio
 	definitions := []*workflow.Definition{}
 	for _, template := range templates {
 		workflowName := filepath.Base(template.LocalPath)
-		destinationPath := filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml")
-		definition := &workflow.Definition{
-			Name:        workflowName,
-			Source:      template.LocalPath,
-			Destination: destinationPath,
-			Status:      workflow.ValidationResult{Valid: true},
-		}
-
-		workflow, err := engine.apply(workflowName, template.LocalPath)
+		outputs, err := engine.apply(workflowName, template.LocalPath)
 
 		if err != nil {
+			definition := engine.newDefinition(workflowName, template)
 			definition.Status.Valid = false
 			definition.Status.Errors = []string{strings.Trim(err.Error(), " \n\r")}
-		} else {
-			definition.SetContent(workflow, template)
+			definition.Status.Diagnostics = ytt.GetDiagnostics(err.Error(), func(file string) string {
+				return engine.resolveSourcePath(workflowName, template.LocalPath, file)
+			}, engine.readSourceLine)
+			definitions = append(definitions, definition)
+			continue
 		}
 
-		definitions = append(definitions, definition)
+		for _, output := range outputs {
+			definition := engine.newDefinition(output.name, template)
+			definition.SetContent(output.content, template)
+			definitions = append(definitions, definition)
+		}
 	}
 
+	// Templates with several outputs may generate the same file as another template
+	workflow.CheckDestinations(definitions)
+
 	return definitions, nil
 }
 
+func (engine *YttTemplateEngine) newDefinition(workflowName string, template *pkg.PathInfo) *workflow.Definition {
+	return &workflow.Definition{
+		Name:        workflowName,
+		Source:      template.LocalPath,
+		Description: template.Description,
+		Destination: filepath.Join(engine.context.GitHubDir, "workflows/", workflowName+".yml"),
+		Status:      workflow.ValidationResult{Valid: true},
+	}
+}
+
+// ImportWorkflow - imports the workflow as a ytt template, keeping the order of its keys and its comments
 func (engine *YttTemplateEngine) ImportWorkflow(workflow *workflow.GitHubWorkflow) (string, error) {
-	workflowContent, err := engine.fs.ReadFile(workflow.Path)
+	document, err := engine.parseWorkflow(workflow)
+	if err != nil {
+		return "", err
+	}
+
+	templateContent, err := yamlutil.EncodeNode(document)
+	if err != nil {
+		return "", err
+	}
+	return engine.writeImportedTemplate(workflow, templateContent), nil
+}
+
+// yttCommonLib - the name of the lib for the steps and jobs extracted by import --extract-common
+const yttCommonLib = "common.lib.yml"
+
+// ImportCommonElements - writes the common steps and jobs to a lib with a function for each element
+func (engine *YttTemplateEngine) ImportCommonElements(common *workflow.CommonElements) (string, error) {
+	functions := []string{}
+	for _, element := range append(common.Steps, common.Jobs...) {
+		value, err := yamlutil.EncodeNode(yamlutil.MapComments(element.Value, escapeYttAnnotation))
+		if err != nil {
+			return "", err
+		}
+		lines := strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = "  " + line
+			}
+		}
+		functions = append(functions, fmt.Sprintf("#@ def %s():\n%s\n#@ end\n", element.Name, strings.Join(lines, "\n")))
+	}
+
+	libPath := filepath.Join(engine.context.LibsDir(), yttCommonLib)
+	exists, err := engine.fs.Exists(libPath)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", fmt.Errorf("can't extract common steps and jobs, %s already exists", libPath)
+	}
+	if err := engine.contentWriter.SafelyWriteFile(libPath, strings.Join(functions, "\n")); err != nil {
+		return "", err
+	}
+	return libPath, nil
+}
+
+// ImportWorkflowWithCommonElements - imports the workflow, replacing its common steps and jobs with calls to the
+// functions in the common lib
+func (engine *YttTemplateEngine) ImportWorkflowWithCommonElements(wf *workflow.GitHubWorkflow, common *workflow.CommonElements) (string, error) {
+	document, err := engine.parseWorkflow(wf)
+	if err != nil {
+		return "", err
+	}
+
+	// The calls are annotations on empty values, e.g. "- #@ setup_go()"
+	document, referenced, err := common.ReplaceCommonElements(document, func(element *workflow.CommonElement) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", LineComment: fmt.Sprintf("#@ %s()", element.Name)}
+	})
 	if err != nil {
 		return "", err
 	}
 
-	templateContent, err := yamlutil.NormalizeWorkflow(string(workflowContent))
+	templateContent, err := yamlutil.EncodeNode(document)
 	if err != nil {
 		return "", err
 	}
 
+	if len(referenced) > 0 {
+		load := []string{fmt.Sprintf("%q", yttCommonLib)}
+		for _, element := range referenced {
+			load = append(load, fmt.Sprintf("%q", element.Name))
+		}
+		templateContent = fmt.Sprintf("#@ load(%s)\n\n%s", strings.Join(load, ", "), templateContent)
+	}
+	return engine.writeImportedTemplate(wf, templateContent), nil
+}
+
+// parseWorkflow - parses the workflow, converting any comments which look like annotations to ytt comments (since ytt
+// would otherwise interpret them). Other comments are kept, and ytt templates copy them to the generated workflows.
+func (engine *YttTemplateEngine) parseWorkflow(wf *workflow.GitHubWorkflow) (*yaml.Node, error) {
+	workflowContent, err := wf.ReadContent(engine.fs)
+	if err != nil {
+		return nil, err
+	}
+	document, err := yamlutil.ParseWorkflowNode(workflowContent)
+	if err != nil {
+		return nil, err
+	}
+	return yamlutil.MapComments(document, escapeYttAnnotation), nil
+}
+
+// escapeYttAnnotation - converts a comment which ytt would treat as an annotation to a ytt comment, e.g. "#@ foo" =>
+// "#!@ foo"
+func escapeYttAnnotation(comment string) string {
+	comment = strings.TrimLeft(comment, " ")
+	if strings.HasPrefix(comment, "#@") {
+		return "#!" + strings.TrimPrefix(comment, "#")
+	}
+	return comment
+}
+
+// GetImportedTemplatePath - imported templates are written to a directory named after the workflow, so that values
+// and other files can be added alongside them
+func (engine *YttTemplateEngine) GetImportedTemplatePath(workflow *workflow.GitHubWorkflow) string {
 	_, filename := filepath.Split(workflow.Path)
 	templateName := strings.TrimSuffix(filename, filepath.Ext(filename))
-	templatePath := filepath.Join(engine.context.WorkflowsDir(), templateName, templateName+".yml")
-	engine.contentWriter.SafelyWriteFile(templatePath, string(templateContent))
+	return filepath.Join(engine.context.WorkflowsDir(), templateName, templateName+".yml")
+}
 
-	return templatePath, nil
+func (engine *YttTemplateEngine) writeImportedTemplate(workflow *workflow.GitHubWorkflow, templateContent string) string {
+	templatePath := engine.GetImportedTemplatePath(workflow)
+	engine.contentWriter.SafelyWriteFile(templatePath, templateContent)
+	return templatePath
 }
 
 func (engine *YttTemplateEngine) WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator {
@@ -207,14 +325,30 @@ func (engine *YttTemplateEngine) getInput(workflowName string, templateDir strin
 		return nil, err
 	}
 	in.Files = append(in.Files, libs...)
+
+	// Data values and overlays come last, so that they apply to the templates and the libs
+	dataValues := engine.context.Config.GetTemplateDataValues("ytt", workflowName)
+	overlays := engine.context.Config.GetTemplateOverlays("ytt", workflowName)
+	for _, path := range engine.context.ResolvePaths(append(dataValues, overlays...)) {
+		// ytt may read the file more than once, so cache it in case it's remote
+		source := files.NewCachedSource(ytt.NewContentSource(engine.contentReader, path, filepath.Base(path)))
+		file, err := files.NewFileFromSource(source)
+		if err != nil {
+			return nil, err
+		}
+		in.Files = append(in.Files, file)
+	}
+
+	// ytt requires an order for every file when there is more than one, so assign one to all the files
+	in.Files = files.NewSortedFiles(in.Files)
 	return &in, nil
 }
 
-func (engine *YttTemplateEngine) apply(workflowName string, templateDir string) (string, error) {
+func (engine *YttTemplateEngine) apply(workflowName string, templateDir string) ([]*yttOutput, error) {
 	ui := cmdcore.NewPlainUI(false)
 	in, err := engine.getInput(workflowName, templateDir)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	rootLibrary := workspace.NewRootLibrary(in.Files)
 
@@ -226,23 +360,145 @@ func (engine *YttTemplateEngine) apply(workflowName string, templateDir string)
 	libraryCtx := workspace.LibraryExecutionContext{Current: rootLibrary, Root: rootLibrary}
 	libraryLoader := libraryExecutionFactory.New(libraryCtx)
 
-	values, libraryValues, err := libraryLoader.Values([]*workspace.DataValues{})
+	dataValuesFlags := cmdtpl.DataValuesFlags{KVsFromStrings: engine.context.DataValues}
+	valuesOverlays, libraryValuesOverlays, err := dataValuesFlags.AsOverlays(false)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	values, libraryValues, err := libraryLoader.Values(valuesOverlays)
+	if err != nil {
+		return nil, err
+	}
+	libraryValues = append(libraryValues, libraryValuesOverlays...)
+
 	result, err := libraryLoader.Eval(values, libraryValues)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	formatter := ytt.NewFormatter(func(file string) ([]byte, error) {
+		return engine.fs.ReadFile(engine.resolveSourcePath(workflowName, templateDir, file))
+	})
+	return getYttOutputs(workflowName, result.Files, ytt.GetOutputDocuments(result.DocSet), formatter)
+}
+
+// yttOutput - a workflow generated by a ytt template
+type yttOutput struct {
+	name    string
+	content string
+}
+
+// getYttOutputs - maps the files output by ytt to workflows. If there's only one YAML document in the output then the
+// workflow is named after the template directory. Otherwise each file is a separate workflow named after the file,
+// and files with several documents give a workflow for each document, numbered in order. The documents are formatted
+// with the comments and formatting of their templates (see ytt.Formatter).
+func getYttOutputs(workflowName string, outputFiles []files.OutputFile, documents []*yamlmeta.Document, formatter *ytt.Formatter) ([]*yttOutput, error) {
+	outputs := []*yttOutput{}
+	outputDocuments := []*ytt.OutputDocument{}
+	for _, file := range outputFiles {
+		_, filename := filepath.Split(file.RelativePath())
+		name := strings.TrimSuffix(filename, filepath.Ext(filename))
+		fileDocuments := splitYamlDocuments(string(file.Bytes()))
+		for index, document := range fileDocuments {
+			output := &yttOutput{name: name, content: document}
+			if len(fileDocuments) > 1 {
+				output.name = fmt.Sprintf("%s-%d", name, index+1)
+			}
+			outputs = append(outputs, output)
+			outputDocument := &ytt.OutputDocument{File: file.RelativePath(), Last: index == len(fileDocuments)-1}
+			if len(outputDocuments) < len(documents) {
+				outputDocument.Document = documents[len(outputDocuments)]
+			}
+			outputDocuments = append(outputDocuments, outputDocument)
+		}
+	}
+
+	// The documents printed by ytt should correspond to the evaluated documents, but only format them if they do
+	if len(outputs) == len(documents) {
+		for index, output := range outputs {
+			content, err := formatter.Format(output.content, outputDocuments[index])
+			if err != nil {
+				return nil, err
+			}
+			output.content = content
+		}
 	}
 
-	workflowContent := ""
+	if len(outputs) == 0 {
+		return []*yttOutput{{name: workflowName, content: ""}}, nil
+	}
+	if len(outputs) == 1 {
+		return []*yttOutput{{name: workflowName, content: outputs[0].content}}, nil
+	}
+	return outputs, nil
+}
+
+// splitYamlDocuments - splits the output of ytt into its (non-empty) documents. ytt only emits document separators at
+// the start of a line, so there's no need to parse the content.
+func splitYamlDocuments(content string) []string {
+	documents := []string{}
+	document := ""
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimRight(line, "\n") == "---" {
+			if strings.TrimSpace(document) != "" {
+				documents = append(documents, document)
+			}
+			document = ""
+			continue
+		}
+		document = document + line
+	}
+	if strings.TrimSpace(document) != "" {
+		documents = append(documents, document)
+	}
+	return documents
+}
 
-	for _, file := range result.Files {
-		workflowContent = workflowContent + string(file.Bytes())
+// resolveSourcePath - maps a file name in a ytt error to the path of the source file. ytt names files relative to
+// the directory they were loaded from, so look for it in the template directory and then the libs, data values and
+// overlays for the workflow.
+func (engine *YttTemplateEngine) resolveSourcePath(workflowName string, templateDir string, file string) string {
+	if path := filepath.Join(templateDir, file); engine.fileExists(path) {
+		return path
+	}
+	libPaths, err := engine.env.GetLibPaths(workflowName)
+	if err == nil {
+		for _, libPath := range libPaths {
+			if path := filepath.Join(libPath, file); engine.fileExists(path) {
+				return path
+			}
+		}
+	}
+	dataValues := engine.context.Config.GetTemplateDataValues("ytt", workflowName)
+	overlays := engine.context.Config.GetTemplateOverlays("ytt", workflowName)
+	for _, path := range engine.context.ResolvePaths(append(dataValues, overlays...)) {
+		if filepath.Base(path) == file {
+			return path
+		}
 	}
+	return file
+}
+
+func (engine *YttTemplateEngine) fileExists(path string) bool {
+	exists, err := engine.fs.Exists(path)
+	return err == nil && exists
+}
 
-	return workflowContent, nil
+// readSourceLine - returns the given (1-indexed) line of a source file, or an empty string if it can't be read
+func (engine *YttTemplateEngine) readSourceLine(path string, line int) string {
+	if !engine.fileExists(path) {
+		return ""
+	}
+	source, err := engine.fs.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(source), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line-1], "\r")
 }
 
 func (engine *YttTemplateEngine) getWorkflowName(workflowsDir string, filename string) string {
@@ -251,13 +507,7 @@ func (engine *YttTemplateEngine) getWorkflowName(workflowsDir string, filename s
 }
 
 func (engine *YttTemplateEngine) getAllYttLibs() []string {
-	libs := engine.context.Config.Templates.Defaults.Libs
-
-	for _, override := range engine.context.Config.Templates.Overrides {
-		libs = append(libs, override.Libs...)
-	}
-
-	return engine.context.ResolvePaths(libs)
+	return engine.context.ResolvePaths(engine.context.Config.GetAllEngineLibs("ytt"))
 }
 
 func (engine *YttTemplateEngine) isLib(path string) bool {
@@ -21,7 +21,7 @@ func newYttTemplateEngine(config string) (*content.Container, *config.GFlowsCont
 	container := content.NewContainer(ioContainer, &http.Client{Transport: roundTripper})
 	installer := env.NewGFlowsLibInstaller(container.FileSystem(), container.ContentReader(), container.ContentWriter(), container.Logger())
 	env := env.NewGFlowsEnv(container.FileSystem(), installer, context, container.Logger())
-	templateEngine := NewYttTemplateEngine(container.FileSystem(), context, container.ContentWriter(), env, container.Logger())
+	templateEngine := NewYttTemplateEngine(container.FileSystem(), context, container.ContentReader(), container.ContentWriter(), env, container.Logger())
 	return container, context, templateEngine, roundTripper
 }
 
@@ -32,7 +32,7 @@ func TestGenerateYttWorkflowDefinitions(t *testing.T) {
 
 	definitions, _ := templateEngine.GetWorkflowDefinitions()
 
-	expectedContent := "# File generated by gflows, do not modify\n# Source: .gflows/workflows/test\n"
+	expectedContent := fixtures.GeneratedWorkflow(".gflows/workflows/test", "")
 	expectedJson, _ := yamlutil.YamlToJson(expectedContent)
 	expectedDefinition := workflow.Definition{
 		Name:        "test",
@@ -55,6 +55,10 @@ func TestGetYttObservableSources(t *testing.T) {
 		"    - vendor",
 		"    - foo/bar.yml",
 		"    - https://example.com/config.yml",
+		"  engines:",
+		"    jsonnet:",
+		"      libs:",
+		"      - jsonnet-libs",
 	}, "\n")
 	container, _, templateEngine, _ := newYttTemplateEngine(config)
 	fs := container.FileSystem()
@@ -66,6 +70,7 @@ func TestGetYttObservableSources(t *testing.T) {
 	fs.WriteFile(".gflows/libs/lib.yml", []byte(""), 0644)
 	fs.WriteFile("vendor/lib/config.yml", []byte(""), 0644)
 	fs.WriteFile("foo/bar.yml", []byte(""), 0644)
+	fs.WriteFile("jsonnet-libs/lib.libsonnet", []byte(""), 0644)
 
 	sources, err := templateEngine.GetObservableSources()
 
@@ -142,3 +147,125 @@ func TestIsLib(t *testing.T) {
 	assert.Equal(t, true, engine.isLib(".gflows/my-lib/"))
 	assert.Equal(t, false, engine.isLib(".gflows/my-workflow.yml"))
 }
+
+func TestGenerateYttMultipleWorkflowDefinitions(t *testing.T) {
+	container, _, templateEngine, _ := newYttTemplateEngine("")
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/deploy/deploy-staging.yml", []byte("name: deploy-staging\n"), 0644)
+	fs.WriteFile(".gflows/workflows/deploy/deploy-production.yml", []byte("name: deploy-production\n"), 0644)
+	fs.WriteFile(".gflows/workflows/checks/checks.yml", []byte("name: lint\n---\nname: test\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 4)
+
+	assert.Equal(t, "checks-1", definitions[0].Name)
+	assert.Equal(t, ".gflows/workflows/checks", definitions[0].Source)
+	assert.Equal(t, ".github/workflows/checks-1.yml", definitions[0].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/checks", "name: lint\n"), definitions[0].Content)
+
+	assert.Equal(t, "checks-2", definitions[1].Name)
+	assert.Equal(t, ".github/workflows/checks-2.yml", definitions[1].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/checks", "name: test\n"), definitions[1].Content)
+
+	assert.Equal(t, "deploy-production", definitions[2].Name)
+	assert.Equal(t, ".gflows/workflows/deploy", definitions[2].Source)
+	assert.Equal(t, ".github/workflows/deploy-production.yml", definitions[2].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/deploy", "name: deploy-production\n"), definitions[2].Content)
+
+	assert.Equal(t, "deploy-staging", definitions[3].Name)
+	assert.Equal(t, ".github/workflows/deploy-staging.yml", definitions[3].Destination)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/deploy", "name: deploy-staging\n"), definitions[3].Content)
+}
+
+func TestGenerateYttWorkflowDefinitionsWithDuplicateOutputs(t *testing.T) {
+	container, _, templateEngine, _ := newYttTemplateEngine("")
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/deploy/deploy-staging.yml", []byte("name: deploy-staging\n"), 0644)
+	fs.WriteFile(".gflows/workflows/deploy/deploy-production.yml", []byte("name: deploy-production\n"), 0644)
+	fs.WriteFile(".gflows/workflows/deploy-staging/deploy-staging.yml", []byte("name: deploy-staging\n"), 0644)
+	fs.WriteFile(".gflows/workflows/checks/lint/test.yml", []byte("name: lint\n"), 0644)
+	fs.WriteFile(".gflows/workflows/checks/unit/test.yml", []byte("name: test\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 5)
+
+	expectedErrors := map[string]string{
+		".gflows/workflows/checks": ".github/workflows/test.yml is generated more than once by .gflows/workflows/checks",
+		".gflows/workflows/deploy": ".github/workflows/deploy-staging.yml is generated by more than one template " +
+			"(.gflows/workflows/deploy, .gflows/workflows/deploy-staging)",
+		".gflows/workflows/deploy-staging": ".github/workflows/deploy-staging.yml is generated by more than one template " +
+			"(.gflows/workflows/deploy, .gflows/workflows/deploy-staging)",
+	}
+	for _, definition := range definitions {
+		if definition.Name == "deploy-production" {
+			assert.Equal(t, workflow.ValidationResult{Valid: true}, definition.Status)
+			continue
+		}
+		assert.Equal(t, workflow.ValidationResult{
+			Valid:  false,
+			Errors: []string{expectedErrors[definition.Source]},
+		}, definition.Status, "errors for %s", definition.Name)
+	}
+}
+
+func TestSplitYamlDocuments(t *testing.T) {
+	assert.Equal(t, []string{"a: 1\n"}, splitYamlDocuments("a: 1\n"))
+	assert.Equal(t, []string{"a: 1\n", "b: |\n  ---\n"}, splitYamlDocuments("---\na: 1\n---\nb: |\n  ---\n---\n"))
+	assert.Equal(t, []string{}, splitYamlDocuments(""))
+}
+
+func TestGenerateYttWorkflowWithDataValuesAndOverlays(t *testing.T) {
+	container, context, templateEngine, _ := newYttTemplateEngine(strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"  overrides:",
+		"    deploy:",
+		"      dataValues: [.gflows/values/production.yml]",
+		"      overlays: [overlays/timeout.yml]",
+	}, "\n"))
+	context.DataValues = []string{"region=eu-west-1"}
+	fs := container.FileSystem()
+	fs.WriteFile(".gflows/workflows/deploy/values.yml", []byte("#@data/values\n---\nenv: staging\nregion: us-east-1\n"), 0644)
+	fs.WriteFile(".gflows/workflows/deploy/deploy.yml", []byte("#@ load(\"@ytt:data\", \"data\")\nenv: #@ data.values.env\nregion: #@ data.values.region\n"), 0644)
+	fs.WriteFile(".gflows/values/production.yml", []byte("#@data/values\n---\nenv: production\n"), 0644)
+	fs.WriteFile(".gflows/overlays/timeout.yml", []byte("#@ load(\"@ytt:overlay\", \"overlay\")\n#@overlay/match by=overlay.all\n---\n#@overlay/match missing_ok=True\ntimeout: 10\n"), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	assert.Equal(t, workflow.ValidationResult{Valid: true}, definitions[0].Status)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/deploy", "env: production\nregion: eu-west-1\ntimeout: 10\n"), definitions[0].Content)
+}
+
+func TestGenerateYttWorkflowWithCommentsAndStyles(t *testing.T) {
+	container, _, templateEngine, _ := newYttTemplateEngine("templates:\n  engine: ytt")
+	fs := container.FileSystem()
+	template := strings.Join([]string{
+		`# Runs the tests`,
+		`"on":`,
+		`  push:`,
+		`    branches: ['develop']`,
+		`jobs:`,
+		`  test:`,
+		`    #! not included`,
+		`    # keep this up to date`,
+		`    runs-on: ubuntu-latest`,
+		`    steps:`,
+		`    - run: make test # runs all the tests`,
+		`# end of the workflow`,
+		``,
+	}, "\n")
+	fs.WriteFile(".gflows/workflows/test/config.yml", []byte(template), 0644)
+
+	definitions, err := templateEngine.GetWorkflowDefinitions()
+
+	assert.NoError(t, err)
+	assert.Len(t, definitions, 1)
+	expectedContent := strings.Replace(template, "    #! not included\n", "", 1)
+	assert.Equal(t, fixtures.GeneratedWorkflow(".gflows/workflows/test", expectedContent), definitions[0].Content)
+}
@@ -1,6 +1,23 @@
 package workflow
 
+import (
+	"github.com/spf13/afero"
+)
+
 type GitHubWorkflow struct {
 	Path       string
 	Definition *Definition
 }
+
+// ReadContent - returns the content to import for the workflow. If the workflow has a definition then this is the
+// generated workflow (without its header), otherwise it's the content of the file at Path.
+func (workflow *GitHubWorkflow) ReadContent(fs *afero.Afero) (string, error) {
+	if workflow.Definition != nil {
+		return getWorkflowBody(workflow.Definition.Content), nil
+	}
+	content, err := fs.ReadFile(workflow.Path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
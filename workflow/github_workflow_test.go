@@ -0,0 +1,24 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/jbrunton/gflows/io/pkg"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadContent(t *testing.T) {
+	fs := &afero.Afero{Fs: afero.NewMemMapFs()}
+	fs.WriteFile(".github/workflows/test.yml", []byte("on: push\n"), 0644)
+	definition := &Definition{}
+	definition.SetContent("on: pull_request\n", &pkg.PathInfo{Description: "workflows/test.jsonnet"})
+
+	content, err := (&GitHubWorkflow{Path: ".github/workflows/test.yml"}).ReadContent(fs)
+	assert.NoError(t, err)
+	assert.Equal(t, "on: push\n", content)
+
+	content, err = (&GitHubWorkflow{Path: ".github/workflows/test.yml", Definition: definition}).ReadContent(fs)
+	assert.NoError(t, err)
+	assert.Equal(t, "on: pull_request\n", content)
+}
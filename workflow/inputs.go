@@ -0,0 +1,207 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/thoas/go-funk"
+)
+
+var dispatchInputTypes = []string{"string", "choice", "boolean", "number", "environment"}
+var callInputTypes = []string{"string", "boolean", "number"}
+
+var expressionRegex = regexp.MustCompile(`(?s)\${{(.*?)}}`)
+var inputReferenceRegex = regexp.MustCompile(`(?:^|[^\w.])(?:github\.event\.)?inputs\.([A-Za-z_][\w-]*)`)
+var jobOutputReferenceRegex = regexp.MustCompile(`(?:^|[^\w.])jobs\.([A-Za-z_][\w-]*)\.outputs\.([A-Za-z_][\w-]*)`)
+
+// checkInputs - returns errors for any invalid workflow_dispatch or workflow_call declarations, and for any
+// references to inputs which aren't declared
+func checkInputs(workflowJSON interface{}) []string {
+	errors := []string{}
+	on, _ := getOn(workflowJSON).(map[string]interface{})
+	declaredInputs := []string{}
+
+	if dispatch, ok := on["workflow_dispatch"].(map[string]interface{}); ok {
+		inputs, _ := dispatch["inputs"].(map[string]interface{})
+		for _, name := range sortedKeys(inputs) {
+			path := fmt.Sprintf("on.workflow_dispatch.inputs.%s", name)
+			errors = append(errors, checkInputDeclaration(path, inputs[name], dispatchInputTypes, "string")...)
+			declaredInputs = append(declaredInputs, name)
+		}
+	}
+
+	if call, ok := on["workflow_call"].(map[string]interface{}); ok {
+		inputs, _ := call["inputs"].(map[string]interface{})
+		for _, name := range sortedKeys(inputs) {
+			path := fmt.Sprintf("on.workflow_call.inputs.%s", name)
+			errors = append(errors, checkInputDeclaration(path, inputs[name], callInputTypes, "")...)
+			declaredInputs = append(declaredInputs, name)
+		}
+
+		secrets, _ := call["secrets"].(map[string]interface{})
+		for _, name := range sortedKeys(secrets) {
+			secret, _ := secrets[name].(map[string]interface{})
+			if required, ok := secret["required"]; ok {
+				if _, isBool := required.(bool); !isBool {
+					errors = append(errors, fmt.Sprintf("on.workflow_call.secrets.%s.required: expected a boolean but found %v", name, required))
+				}
+			}
+		}
+
+		outputs, _ := call["outputs"].(map[string]interface{})
+		jobs := getJobs(workflowJSON)
+		for _, name := range sortedKeys(outputs) {
+			path := fmt.Sprintf("on.workflow_call.outputs.%s", name)
+			output, _ := outputs[name].(map[string]interface{})
+			value, ok := output["value"].(string)
+			if !ok {
+				errors = append(errors, fmt.Sprintf("%s: value is required", path))
+				continue
+			}
+			errors = append(errors, checkJobOutputReferences(path+".value", value, jobs)...)
+		}
+	}
+
+	walkStrings(workflowJSON, "", func(path string, value string) {
+		if strings.HasPrefix(path, "on.") {
+			return
+		}
+		for _, name := range getInputReferences(path, value) {
+			if !funk.ContainsString(declaredInputs, name) {
+				errors = append(errors, fmt.Sprintf("%s: input %q is not declared by workflow_dispatch or workflow_call", path, name))
+			}
+		}
+	})
+
+	return errors
+}
+
+// checkInputDeclaration - checks the type of an input, and that its default and options are consistent with it
+func checkInputDeclaration(path string, declaration interface{}, validTypes []string, defaultType string) []string {
+	errors := []string{}
+	input, _ := declaration.(map[string]interface{})
+
+	inputType := defaultType
+	if value, ok := input["type"]; ok {
+		inputType = fmt.Sprintf("%v", value)
+	}
+	if inputType == "" {
+		return append(errors, fmt.Sprintf("%s.type: type is required", path))
+	}
+	if !funk.ContainsString(validTypes, inputType) {
+		return append(errors, fmt.Sprintf("%s.type: unexpected type %q (expected one of %s)", path, inputType, strings.Join(validTypes, ", ")))
+	}
+
+	options, hasOptions := input["options"]
+	optionsList, _ := options.([]interface{})
+	if inputType == "choice" && len(optionsList) == 0 {
+		errors = append(errors, fmt.Sprintf("%s.options: choice inputs require at least one option", path))
+	} else if inputType != "choice" && hasOptions {
+		errors = append(errors, fmt.Sprintf("%s.options: options are only valid for choice inputs", path))
+	}
+
+	if defaultValue, ok := input["default"]; ok {
+		if !isValidInputValue(inputType, defaultValue, optionsList) {
+			errors = append(errors, fmt.Sprintf("%s.default: %s is not a valid %s value", path, formatInputValue(defaultValue), inputType))
+		}
+	}
+
+	return errors
+}
+
+func isValidInputValue(inputType string, value interface{}, options []interface{}) bool {
+	switch inputType {
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		switch value.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	case "choice":
+		return funk.Contains(options, value)
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+func formatInputValue(value interface{}) string {
+	if value, ok := value.(string); ok {
+		return fmt.Sprintf("%q", value)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// checkJobOutputReferences - returns errors for any references to jobs.<job>.outputs.<output> in the given value
+// where the job doesn't exist, or doesn't declare the output
+func checkJobOutputReferences(path string, value string, jobs map[string]interface{}) []string {
+	errors := []string{}
+	for _, expression := range getExpressions(value) {
+		for _, match := range jobOutputReferenceRegex.FindAllStringSubmatch(expression, -1) {
+			jobName, outputName := match[1], match[2]
+			job, ok := jobs[jobName].(map[string]interface{})
+			if !ok {
+				errors = append(errors, fmt.Sprintf("%s: job %q does not exist", path, jobName))
+				continue
+			}
+			outputs, _ := job["outputs"].(map[string]interface{})
+			if _, ok := outputs[outputName]; !ok {
+				errors = append(errors, fmt.Sprintf("%s: job %q does not declare output %q", path, jobName, outputName))
+			}
+		}
+	}
+	return errors
+}
+
+// getInputReferences - returns the names of inputs referenced by expressions in the value at the given path
+func getInputReferences(path string, value string) []string {
+	names := []string{}
+	for _, expression := range getValueExpressions(path, value) {
+		for _, match := range inputReferenceRegex.FindAllStringSubmatch(expression, -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// getExpressions - returns the contents of any ${{ }} expressions in the given value
+func getExpressions(value string) []string {
+	expressions := []string{}
+	for _, match := range expressionRegex.FindAllStringSubmatch(value, -1) {
+		expressions = append(expressions, match[1])
+	}
+	return expressions
+}
+
+// getValueExpressions - returns the expressions in the value at the given path. Since if conditions may omit the
+// ${{ }} syntax, they're treated as a single expression when it's missing.
+func getValueExpressions(path string, value string) []string {
+	if strings.HasSuffix(path, ".if") && !strings.Contains(value, "${{") {
+		return []string{value}
+	}
+	return getExpressions(value)
+}
+
+// walkStrings - calls fn for every string value in the JSON, along with its path
+func walkStrings(value interface{}, path string, fn func(path string, value string)) {
+	switch value := value.(type) {
+	case string:
+		fn(path, value)
+	case map[string]interface{}:
+		for _, key := range sortedKeys(value) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			walkStrings(value[key], childPath, fn)
+		}
+	case []interface{}:
+		for i, item := range value {
+			walkStrings(item, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/thoas/go-funk"
+)
+
+// MaxMatrixJobs - the maximum number of jobs GitHub will generate for a matrix
+const MaxMatrixJobs = 256
+
+// ErrDynamicMatrix - returned when a matrix is (partially) generated by an expression, and so can only be expanded
+// at runtime
+var ErrDynamicMatrix = errors.New("matrix is generated by an expression, so it can only be expanded at runtime")
+
+// MatrixExpansion - the job combinations generated by a matrix strategy
+type MatrixExpansion struct {
+	// Keys - the sorted keys of all the combinations
+	Keys []string
+	// Combinations - the matrix values for each job
+	Combinations []map[string]interface{}
+	// UnmatchedExcludes - any exclude entries which didn't match a combination
+	UnmatchedExcludes []map[string]interface{}
+}
+
+// GetJobMatrix - returns the strategy.matrix for the given job, or nil if it doesn't have one
+func GetJobMatrix(job interface{}) interface{} {
+	jobMap, ok := job.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	strategy, ok := jobMap["strategy"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return strategy["matrix"]
+}
+
+// ExpandMatrix - expands a matrix into its job combinations, following the same rules as GitHub: the combinations
+// for each matrix vector are generated first, then exclude entries are removed, and then include entries are
+// either merged into matching combinations or added as new combinations
+func ExpandMatrix(matrix interface{}) (*MatrixExpansion, error) {
+	matrixMap, ok := matrix.(map[string]interface{})
+	if !ok {
+		if isExpression(matrix) {
+			return nil, ErrDynamicMatrix
+		}
+		return nil, fmt.Errorf("unexpected matrix: %v (expected a map)", matrix)
+	}
+
+	vectorKeys := []string{}
+	for _, key := range sortedKeys(matrixMap) {
+		if key == "include" || key == "exclude" {
+			continue
+		}
+		if _, ok := matrixMap[key].([]interface{}); !ok {
+			if isExpression(matrixMap[key]) {
+				return nil, ErrDynamicMatrix
+			}
+			return nil, fmt.Errorf("unexpected value for %s: %v (expected a list)", key, matrixMap[key])
+		}
+		vectorKeys = append(vectorKeys, key)
+	}
+
+	includes, err := getMatrixEntries(matrixMap, "include")
+	if err != nil {
+		return nil, err
+	}
+	excludes, err := getMatrixEntries(matrixMap, "exclude")
+	if err != nil {
+		return nil, err
+	}
+
+	combinations := []map[string]interface{}{}
+	if len(vectorKeys) > 0 {
+		combinations = append(combinations, map[string]interface{}{})
+	}
+	for _, key := range vectorKeys {
+		product := []map[string]interface{}{}
+		for _, combination := range combinations {
+			for _, value := range matrixMap[key].([]interface{}) {
+				product = append(product, withMatrixValue(combination, key, value))
+			}
+		}
+		combinations = product
+	}
+
+	unmatchedExcludes := []map[string]interface{}{}
+	for _, exclude := range excludes {
+		remaining := []map[string]interface{}{}
+		for _, combination := range combinations {
+			if !matchesMatrixEntry(combination, exclude, nil) {
+				remaining = append(remaining, combination)
+			}
+		}
+		if len(remaining) == len(combinations) {
+			unmatchedExcludes = append(unmatchedExcludes, exclude)
+		}
+		combinations = remaining
+	}
+
+	originalCombinations := combinations
+	for _, include := range includes {
+		merged := false
+		for _, combination := range originalCombinations {
+			// include entries may overwrite added values, but not values from the original vectors
+			if matchesMatrixEntry(combination, include, vectorKeys) {
+				for key, value := range include {
+					combination[key] = value
+				}
+				merged = true
+			}
+		}
+		if !merged {
+			combinations = append(combinations, withMatrixValues(map[string]interface{}{}, include))
+		}
+	}
+
+	keySet := make(map[string]interface{})
+	for _, combination := range combinations {
+		for key := range combination {
+			keySet[key] = true
+		}
+	}
+
+	return &MatrixExpansion{
+		Keys:              sortedKeys(keySet),
+		Combinations:      combinations,
+		UnmatchedExcludes: unmatchedExcludes,
+	}, nil
+}
+
+// checkMatrices - returns errors for any matrix which exceeds the job limit, or which has exclude entries that
+// don't match anything
+func checkMatrices(workflowJSON interface{}) []string {
+	errors := []string{}
+	jobs := getJobs(workflowJSON)
+	for _, jobName := range sortedKeys(jobs) {
+		matrix := GetJobMatrix(jobs[jobName])
+		if matrix == nil {
+			continue
+		}
+		path := fmt.Sprintf("jobs.%s.strategy.matrix", jobName)
+		expansion, err := ExpandMatrix(matrix)
+		if err == ErrDynamicMatrix {
+			// dynamic matrices can't be checked
+			continue
+		}
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %s", path, err))
+			continue
+		}
+		if len(expansion.Combinations) > MaxMatrixJobs {
+			errors = append(errors, fmt.Sprintf("%s: matrix generates %d jobs, exceeding the limit of %d", path, len(expansion.Combinations), MaxMatrixJobs))
+		}
+		for _, exclude := range expansion.UnmatchedExcludes {
+			errors = append(errors, fmt.Sprintf("%s.exclude: %s doesn't match any combination", path, FormatMatrixValues(exclude)))
+		}
+	}
+	return errors
+}
+
+// FormatMatrixValues - returns a description of the given matrix values, ordered by key
+func FormatMatrixValues(values map[string]interface{}) string {
+	description := ""
+	for i, key := range sortedKeys(values) {
+		if i > 0 {
+			description += ", "
+		}
+		description += fmt.Sprintf("%s: %v", key, values[key])
+	}
+	return "{" + description + "}"
+}
+
+func getMatrixEntries(matrix map[string]interface{}, key string) ([]map[string]interface{}, error) {
+	value, ok := matrix[key]
+	if !ok {
+		return []map[string]interface{}{}, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		if isExpression(value) {
+			return nil, ErrDynamicMatrix
+		}
+		return nil, fmt.Errorf("unexpected value for %s: %v (expected a list)", key, value)
+	}
+	entries := []map[string]interface{}{}
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected %s entry: %v (expected a map)", key, item)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// matchesMatrixEntry - returns true if the combination has the same values as the entry. If keys is non-nil, then
+// only those keys are compared.
+func matchesMatrixEntry(combination map[string]interface{}, entry map[string]interface{}, keys []string) bool {
+	for key, value := range entry {
+		if keys != nil && !funk.ContainsString(keys, key) {
+			continue
+		}
+		actualValue, ok := combination[key]
+		if !ok || !reflect.DeepEqual(actualValue, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func withMatrixValue(combination map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	result := withMatrixValues(map[string]interface{}{}, combination)
+	result[key] = value
+	return result
+}
+
+func withMatrixValues(combination map[string]interface{}, values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		combination[key] = value
+	}
+	return combination
+}
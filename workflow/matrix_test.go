@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseMatrix(lines ...string) interface{} {
+	json, err := yamlutil.YamlToJson(strings.Join(lines, "\n"))
+	if err != nil {
+		panic(err)
+	}
+	return json
+}
+
+func TestExpandMatrix(t *testing.T) {
+	scenarios := []struct {
+		description          string
+		matrix               interface{}
+		expectedCombinations []map[string]interface{}
+		expectedUnmatched    []map[string]interface{}
+	}{
+		{
+			description: "vectors",
+			matrix: parseMatrix(
+				"os: [ubuntu, windows]",
+				"node: [12, 14]",
+			),
+			expectedCombinations: []map[string]interface{}{
+				{"node": 12, "os": "ubuntu"},
+				{"node": 12, "os": "windows"},
+				{"node": 14, "os": "ubuntu"},
+				{"node": 14, "os": "windows"},
+			},
+			expectedUnmatched: []map[string]interface{}{},
+		},
+		{
+			description: "exclude",
+			matrix: parseMatrix(
+				"os: [ubuntu, windows]",
+				"node: [12, 14]",
+				"exclude:",
+				"- os: windows",
+				"  node: 12",
+				"- os: macos",
+			),
+			expectedCombinations: []map[string]interface{}{
+				{"node": 12, "os": "ubuntu"},
+				{"node": 14, "os": "ubuntu"},
+				{"node": 14, "os": "windows"},
+			},
+			expectedUnmatched: []map[string]interface{}{
+				{"os": "macos"},
+			},
+		},
+		{
+			description: "include",
+			matrix: parseMatrix(
+				"os: [ubuntu, windows]",
+				"node: [12]",
+				"include:",
+				"- os: windows",
+				"  shell: pwsh",
+				"- experimental: true",
+				"- os: macos",
+				"  node: 14",
+			),
+			expectedCombinations: []map[string]interface{}{
+				{"experimental": true, "node": 12, "os": "ubuntu"},
+				{"experimental": true, "node": 12, "os": "windows", "shell": "pwsh"},
+				{"node": 14, "os": "macos"},
+			},
+			expectedUnmatched: []map[string]interface{}{},
+		},
+		{
+			description: "include only",
+			matrix: parseMatrix(
+				"include:",
+				"- site: production",
+				"- site: staging",
+			),
+			expectedCombinations: []map[string]interface{}{
+				{"site": "production"},
+				{"site": "staging"},
+			},
+			expectedUnmatched: []map[string]interface{}{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		expansion, err := ExpandMatrix(scenario.matrix)
+		assert.NoError(t, err, "Unexpected error in scenario %q", scenario.description)
+		assert.Equal(t, scenario.expectedCombinations, expansion.Combinations, "Unexpected combinations in scenario %q", scenario.description)
+		assert.Equal(t, scenario.expectedUnmatched, expansion.UnmatchedExcludes, "Unexpected excludes in scenario %q", scenario.description)
+	}
+}
+
+func TestExpandDynamicMatrix(t *testing.T) {
+	_, err := ExpandMatrix("${{ fromJSON(needs.setup.outputs.matrix) }}")
+	assert.Equal(t, ErrDynamicMatrix, err)
+
+	_, err = ExpandMatrix(parseMatrix("os: ${{ fromJSON(needs.setup.outputs.os) }}"))
+	assert.Equal(t, ErrDynamicMatrix, err)
+}
+
+func TestExpandInvalidMatrix(t *testing.T) {
+	_, err := ExpandMatrix("not a matrix")
+	assert.EqualError(t, err, "unexpected matrix: not a matrix (expected a map)")
+
+	_, err = ExpandMatrix(parseMatrix("os: ubuntu-latest"))
+	assert.EqualError(t, err, "unexpected value for os: ubuntu-latest (expected a list)")
+
+	_, err = ExpandMatrix(parseMatrix("os: [ubuntu-latest]", "include: {os: windows-latest}"))
+	assert.EqualError(t, err, "unexpected value for include: map[os:windows-latest] (expected a list)")
+
+	_, err = ExpandMatrix(parseMatrix("os: [ubuntu-latest]", "exclude: [windows-latest]"))
+	assert.EqualError(t, err, "unexpected exclude entry: windows-latest (expected a map)")
+}
@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"fmt"
+)
+
+// permissionLevels - access levels for GITHUB_TOKEN scopes, ordered from least to most privileged
+var permissionLevels = map[string]int{
+	"none":  0,
+	"read":  1,
+	"write": 2,
+}
+
+// permissionScopes - the scopes granted by the read-all and write-all shorthands
+var permissionScopes = []string{
+	"actions",
+	"attestations",
+	"checks",
+	"contents",
+	"deployments",
+	"discussions",
+	"id-token",
+	"issues",
+	"packages",
+	"pages",
+	"pull-requests",
+	"repository-projects",
+	"security-events",
+	"statuses",
+}
+
+// checkPermissions - returns errors for any job which doesn't have an explicit permissions block (either
+// its own or the workflow's), and for any scope which exceeds the given maximum. If max is nil then only
+// the presence of the permissions blocks is checked.
+func checkPermissions(workflowJSON interface{}, max map[string]string) []string {
+	errors := []string{}
+	workflowMap, ok := workflowJSON.(map[string]interface{})
+	if !ok {
+		return errors
+	}
+
+	workflowPermissions, hasWorkflowPermissions := workflowMap["permissions"]
+	if hasWorkflowPermissions {
+		errors = append(errors, checkPermissionsBlock("permissions", workflowPermissions, max)...)
+	}
+
+	jobs := getJobs(workflowJSON)
+	for _, jobName := range sortedKeys(jobs) {
+		job, _ := jobs[jobName].(map[string]interface{})
+		jobPermissions, hasJobPermissions := job["permissions"]
+		if hasJobPermissions {
+			path := fmt.Sprintf("jobs.%s.permissions", jobName)
+			errors = append(errors, checkPermissionsBlock(path, jobPermissions, max)...)
+		} else if !hasWorkflowPermissions {
+			errors = append(errors, fmt.Sprintf("jobs.%s: permissions is required (set for the job or the workflow)", jobName))
+		}
+	}
+
+	return errors
+}
+
+func checkPermissionsBlock(path string, value interface{}, max map[string]string) []string {
+	permissions, err := parsePermissions(value)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %s", path, err)}
+	}
+	if max == nil {
+		return []string{}
+	}
+
+	errors := []string{}
+	for _, scope := range sortedKeys(permissions) {
+		level := permissions[scope]
+		maxLevel, ok := max[scope]
+		if !ok {
+			maxLevel = "none"
+		}
+		if permissionLevels[level] > permissionLevels[maxLevel] {
+			errors = append(errors, fmt.Sprintf("%s.%s: %s exceeds the maximum permitted access (%s)", path, scope, level, maxLevel))
+		}
+	}
+	return errors
+}
+
+// parsePermissions - returns the access level for each scope in a permissions block, which may either be
+// a map of scopes or one of the read-all and write-all shorthands
+func parsePermissions(value interface{}) (map[string]string, error) {
+	permissions := make(map[string]string)
+	switch value := value.(type) {
+	case string:
+		var level string
+		switch value {
+		case "read-all":
+			level = "read"
+		case "write-all":
+			level = "write"
+		default:
+			return nil, fmt.Errorf("unexpected value %q (expected read-all, write-all or a map of scopes)", value)
+		}
+		for _, scope := range permissionScopes {
+			permissions[scope] = level
+		}
+	case map[string]interface{}:
+		for scope, scopeValue := range value {
+			level, ok := scopeValue.(string)
+			if _, valid := permissionLevels[level]; !ok || !valid {
+				return nil, fmt.Errorf("unexpected access level %v for %s (expected read, write or none)", scopeValue, scope)
+			}
+			permissions[scope] = level
+		}
+	case nil:
+		// permissions: {} is parsed as an empty map, but an empty value amounts to the same thing
+	default:
+		return nil, fmt.Errorf("unexpected value %v (expected read-all, write-all or a map of scopes)", value)
+	}
+	return permissions, nil
+}
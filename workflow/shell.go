@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// checkShellScripts - returns errors for any bash or sh run steps which contain syntax errors
+func (validator *Validator) checkShellScripts(definition *Definition) []string {
+	errors := []string{}
+	workflowShell := getDefaultShell(definition.JSON)
+	jobs := definition.GetJobs()
+	for _, jobName := range sortedKeys(jobs) {
+		job, _ := jobs[jobName].(map[string]interface{})
+		jobShell := getDefaultShell(job)
+		if jobShell == "" {
+			jobShell = workflowShell
+		}
+		if jobShell == "" && !isWindowsJob(job) {
+			// bash is the default for all runners other than Windows
+			jobShell = "bash"
+		}
+
+		for i, step := range getSteps(job) {
+			stepMap, _ := step.(map[string]interface{})
+			script, ok := stepMap["run"].(string)
+			if !ok {
+				continue
+			}
+			shell, _ := stepMap["shell"].(string)
+			if shell == "" {
+				shell = jobShell
+			}
+			variant, ok := getShellVariant(shell)
+			if !ok {
+				continue
+			}
+
+			path := fmt.Sprintf("jobs.%s.steps[%d].run", jobName, i)
+			line, message, err := parseShellScript(script, variant)
+			if err == nil {
+				continue
+			}
+			location := fmt.Sprintf("line %d", line)
+			if name, ok := stepMap["name"].(string); ok {
+				location = fmt.Sprintf("step %q, %s", name, location)
+			}
+			if templateLocation := validator.findTemplateLine(definition, script, line); templateLocation != "" {
+				location = fmt.Sprintf("%s, %s", location, templateLocation)
+			}
+			errors = append(errors, fmt.Sprintf("%s: %s (%s)", path, message, location))
+		}
+	}
+	return errors
+}
+
+// parseShellScript - parses the script, returning the line and message for any syntax error. Expressions are masked
+// first, so that they're treated as plain words.
+func parseShellScript(script string, variant syntax.LangVariant) (uint, string, error) {
+	parser := syntax.NewParser(syntax.Variant(variant))
+	_, err := parser.Parse(strings.NewReader(maskExpressions(script)), "")
+	if err == nil {
+		return 0, "", nil
+	}
+	var pos syntax.Pos
+	switch err := err.(type) {
+	case syntax.ParseError:
+		pos = err.Pos
+	case syntax.LangError:
+		pos = err.Pos
+	default:
+		return 0, err.Error(), err
+	}
+	return pos.Line(), strings.TrimPrefix(err.Error(), pos.String()+": "), err
+}
+
+// maskExpressions - replaces the contents of any ${{ }} expressions with placeholder characters, preserving line and
+// column numbers
+func maskExpressions(script string) string {
+	return expressionRegex.ReplaceAllStringFunc(script, func(expression string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return 'x'
+		}, expression)
+	})
+}
+
+// findTemplateLine - tries to find the template line for the given line in a script, returning an empty string if
+// it can't be found unambiguously
+func (validator *Validator) findTemplateLine(definition *Definition, script string, line uint) string {
+	lines := strings.Split(script, "\n")
+	if line < 1 || int(line) > len(lines) {
+		return ""
+	}
+	text := strings.TrimSpace(lines[line-1])
+	if text == "" {
+		return ""
+	}
+
+	location := ""
+	matches := 0
+	err := validator.fs.Walk(definition.Source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := afero.ReadFile(validator.fs, path)
+		if err != nil {
+			return err
+		}
+		for i, templateLine := range strings.Split(string(data), "\n") {
+			if strings.Contains(templateLine, text) {
+				location = fmt.Sprintf("%s:%d", filepath.ToSlash(path), i+1)
+				matches++
+			}
+		}
+		return nil
+	})
+	if err != nil || matches != 1 {
+		return ""
+	}
+	return location
+}
+
+// getDefaultShell - returns the defaults.run.shell value for a workflow or job
+func getDefaultShell(value interface{}) string {
+	valueMap, _ := value.(map[string]interface{})
+	defaults, _ := valueMap["defaults"].(map[string]interface{})
+	run, _ := defaults["run"].(map[string]interface{})
+	shell, _ := run["shell"].(string)
+	return shell
+}
+
+func getShellVariant(shell string) (syntax.LangVariant, bool) {
+	fields := strings.Fields(shell)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	switch filepath.Base(fields[0]) {
+	case "bash":
+		return syntax.LangBash, true
+	case "sh":
+		return syntax.LangPOSIX, true
+	}
+	return 0, false
+}
+
+func isWindowsJob(job map[string]interface{}) bool {
+	return strings.Contains(strings.ToLower(fmt.Sprintf("%v", job["runs-on"])), "windows")
+}
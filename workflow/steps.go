@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var stepOutputReferenceRegex = regexp.MustCompile(`(?:^|[^\w.])steps\.([A-Za-z_][\w-]*)\.outputs\.([A-Za-z_][\w-]*)`)
+
+// checkSteps - returns errors for any duplicate step ids, and for references to the outputs of steps which don't
+// exist or haven't run yet
+func checkSteps(workflowJSON interface{}) []string {
+	errors := []string{}
+	jobs := getJobs(workflowJSON)
+	for _, jobName := range sortedKeys(jobs) {
+		path := fmt.Sprintf("jobs.%s", jobName)
+		steps := getSteps(jobs[jobName])
+
+		stepIndexes := make(map[string]int)
+		for i, step := range steps {
+			stepMap, _ := step.(map[string]interface{})
+			id, ok := stepMap["id"].(string)
+			if !ok {
+				continue
+			}
+			if previous, ok := stepIndexes[id]; ok {
+				errors = append(errors, fmt.Sprintf("%s.steps[%d].id: duplicate step id %q (already used by steps[%d])", path, i, id, previous))
+				continue
+			}
+			stepIndexes[id] = i
+		}
+
+		for i, step := range steps {
+			stepPath := fmt.Sprintf("%s.steps[%d]", path, i)
+			walkStrings(step, stepPath, func(path string, value string) {
+				for _, id := range getStepOutputReferences(path, value) {
+					index, ok := stepIndexes[id]
+					if !ok {
+						errors = append(errors, fmt.Sprintf("%s: step %q does not exist", path, id))
+					} else if index >= i {
+						errors = append(errors, fmt.Sprintf("%s: step %q does not run before this step", path, id))
+					}
+				}
+			})
+		}
+
+		job, _ := jobs[jobName].(map[string]interface{})
+		walkStrings(job["outputs"], path+".outputs", func(path string, value string) {
+			for _, id := range getStepOutputReferences(path, value) {
+				if _, ok := stepIndexes[id]; !ok {
+					errors = append(errors, fmt.Sprintf("%s: step %q does not exist", path, id))
+				}
+			}
+		})
+	}
+	return errors
+}
+
+// getStepOutputReferences - returns the ids of steps whose outputs are referenced by expressions in the value at
+// the given path
+func getStepOutputReferences(path string, value string) []string {
+	ids := []string{}
+	for _, expression := range getValueExpressions(path, value) {
+		for _, match := range stepOutputReferenceRegex.FindAllStringSubmatch(expression, -1) {
+			ids = append(ids, match[1])
+		}
+	}
+	return ids
+}
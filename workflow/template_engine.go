@@ -15,6 +15,9 @@ type TemplateEngine interface {
 	// ImportWorkflow - imports a workflow, returns the path to the new template.
 	ImportWorkflow(workflow *GitHubWorkflow) (string, error)
 
+	// GetImportedTemplatePath - returns the path ImportWorkflow writes the template for the workflow to.
+	GetImportedTemplatePath(workflow *GitHubWorkflow) string
+
 	// WorkflowGenerator - returns a generator to create default workflow and config files
 	WorkflowGenerator(templateVars map[string]string) content.WorkflowGenerator
 }
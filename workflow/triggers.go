@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduleSummary - describes a cron schedule from an on.schedule trigger
+type ScheduleSummary struct {
+	Cron        string      `json:"cron"`
+	Description string      `json:"description,omitempty"`
+	NextRuns    []time.Time `json:"nextRuns,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// GetTriggers - returns the names of the events which trigger the workflow
+func (definition *Definition) GetTriggers() []string {
+	return getTriggers(definition.JSON)
+}
+
+func getTriggers(workflowJSON interface{}) []string {
+	triggers := []string{}
+	switch on := getOn(workflowJSON).(type) {
+	case string:
+		triggers = append(triggers, on)
+	case []interface{}:
+		for _, event := range on {
+			triggers = append(triggers, fmt.Sprintf("%v", event))
+		}
+	case map[string]interface{}:
+		triggers = sortedKeys(on)
+	}
+	sort.Strings(triggers)
+	return triggers
+}
+
+// GetSchedules - returns the cron expressions for any on.schedule triggers
+func (definition *Definition) GetSchedules() []string {
+	schedules := []string{}
+	on, ok := getOn(definition.JSON).(map[string]interface{})
+	if !ok {
+		return schedules
+	}
+	entries, _ := on["schedule"].([]interface{})
+	for _, entry := range entries {
+		entryMap, _ := entry.(map[string]interface{})
+		cron, _ := entryMap["cron"].(string)
+		schedules = append(schedules, cron)
+	}
+	return schedules
+}
+
+// GetScheduleSummaries - returns a summary of each schedule, including the next few times it will run
+func (definition *Definition) GetScheduleSummaries(after time.Time, count int) []ScheduleSummary {
+	summaries := []ScheduleSummary{}
+	for _, cron := range definition.GetSchedules() {
+		summary := ScheduleSummary{Cron: cron}
+		schedule, err := ParseCron(cron)
+		if err != nil {
+			summary.Error = err.Error()
+		} else {
+			summary.Description = schedule.Describe()
+			summary.NextRuns = schedule.Next(after, count)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// checkSchedules - returns errors for any invalid cron expressions, or schedules which run more frequently than
+// GitHub allows
+func checkSchedules(definition *Definition) []string {
+	errors := []string{}
+	for i, cron := range definition.GetSchedules() {
+		path := fmt.Sprintf("on.schedule[%d].cron", i)
+		schedule, err := ParseCron(cron)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %q is invalid: %s", path, cron, err))
+			continue
+		}
+		if interval := schedule.MinInterval(); interval < MinScheduleInterval {
+			errors = append(errors, fmt.Sprintf("%s: %q runs as often as every %d minutes, but the shortest interval GitHub supports is %d minutes", path, cron, interval, MinScheduleInterval))
+		}
+	}
+	return errors
+}
+
+func getOn(workflowJSON interface{}) interface{} {
+	workflowMap, ok := workflowJSON.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return workflowMap["on"]
+}
@@ -2,8 +2,10 @@ package workflow
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/jbrunton/gflows/config"
+	"github.com/jbrunton/gflows/yamlutil"
 	"github.com/spf13/afero"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -13,6 +15,8 @@ type Validator struct {
 	fs            *afero.Afero
 	defaultSchema *gojsonschema.Schema
 	config        *config.GFlowsConfig
+	githubDir     string
+	deprecations  *Deprecations
 }
 
 // ValidationResult - validate result
@@ -20,6 +24,10 @@ type ValidationResult struct {
 	Valid         bool
 	Errors        []string
 	ActualContent string
+	// HandEdited - set by ValidateContent if the workflow was modified after it was generated
+	HandEdited bool
+	// Diagnostics - structured errors for templates which couldn't be evaluated, if the engine provides them
+	Diagnostics []Diagnostic
 }
 
 // NewValidator - creates a new validator for the given filesystem
@@ -34,6 +42,8 @@ func NewValidator(fs *afero.Afero, context *config.GFlowsContext) *Validator {
 		fs:            fs,
 		defaultSchema: defaultSchema,
 		config:        config,
+		githubDir:     context.GitHubDir,
+		deprecations:  LoadDeprecations(),
 	}
 }
 
@@ -96,6 +106,23 @@ func (validator *Validator) ValidateContent(definition *Definition) ValidationRe
 
 	actualContent := string(data)
 	if actualContent != definition.Content {
+		if validator.getContentCheckMode(definition) == "semantic" && validator.isSemanticallyEqual(actualContent, definition) {
+			warning := fmt.Sprintf("Content for %q differs only in formatting (%s)", definition.Name, definition.Destination)
+			return ValidationResult{
+				Valid:         true,
+				Errors:        []string{warning},
+				ActualContent: actualContent,
+			}
+		}
+		if validator.IsHandEdited(definition, actualContent) {
+			reason := fmt.Sprintf("Workflow %q has been modified since it was generated (%s)", definition.Name, definition.Destination)
+			return ValidationResult{
+				Valid:         false,
+				Errors:        []string{reason},
+				ActualContent: actualContent,
+				HandEdited:    true,
+			}
+		}
 		reason := fmt.Sprintf("Content is out of date for %q (%s)", definition.Name, definition.Destination)
 		return ValidationResult{
 			Valid:         false,
@@ -110,6 +137,205 @@ func (validator *Validator) ValidateContent(definition *Definition) ValidationRe
 	}
 }
 
+// ValidatePermissions - validates the workflow declares explicit permissions for every job, and that they don't
+// exceed the configured maximum
+func (validator *Validator) ValidatePermissions(definition *Definition) ValidationResult {
+	enabled := validator.getPermissionsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{},
+		}
+	}
+
+	max := validator.config.GetWorkflowMapProperty(definition.Name, func(config *config.GFlowsWorkflowConfig) map[string]string {
+		return config.Checks.Permissions.Max
+	})
+	errors := checkPermissions(definition.JSON, max)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// IsHandEdited - returns true if the content has been modified since it was generated. In semantic mode, changes which
+// don't affect the workflow (e.g. to formatting or comments) are ignored, so that check and update agree on them.
+func (validator *Validator) IsHandEdited(definition *Definition, actualContent string) bool {
+	if !IsHandEdited(actualContent) {
+		return false
+	}
+	return validator.getContentCheckMode(definition) != "semantic" || !validator.isSemanticallyEqual(actualContent, definition)
+}
+
+// isSemanticallyEqual - returns true if the given content parses to the same workflow as the definition, so that
+// differences in formatting, comments and quoting are ignored
+func (validator *Validator) isSemanticallyEqual(actualContent string, definition *Definition) bool {
+	if definition.JSON == nil {
+		return false
+	}
+	// Formatters may unquote the "on" key, so normalize it before parsing
+	normalizedContent, err := yamlutil.NormalizeWorkflow(actualContent)
+	if err != nil {
+		return false
+	}
+	actualJSON, err := yamlutil.YamlToJson(normalizedContent)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(actualJSON, definition.JSON)
+}
+
+// ValidateMatrices - validates that matrix strategies don't exceed the job limit, and that their exclude entries
+// match at least one combination
+func (validator *Validator) ValidateMatrices(definition *Definition) ValidationResult {
+	enabled := validator.getMatrixCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Matrix checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := checkMatrices(definition.JSON)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateSchedules - validates the cron expressions for any schedule triggers
+func (validator *Validator) ValidateSchedules(definition *Definition) ValidationResult {
+	enabled := validator.getScheduleCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Schedule checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := checkSchedules(definition)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateInputs - validates the inputs, outputs and secrets declared by workflow_dispatch and workflow_call
+// triggers, and that any referenced inputs are declared
+func (validator *Validator) ValidateInputs(definition *Definition) ValidationResult {
+	enabled := validator.getInputsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Inputs checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := checkInputs(definition.JSON)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateWorkflowCalls - validates any calls to local reusable workflows, using the given definitions to resolve
+// generated workflows
+func (validator *Validator) ValidateWorkflowCalls(definition *Definition, definitions []*Definition) ValidationResult {
+	enabled := validator.getCallsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Workflow call checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := validator.checkWorkflowCalls(definition, definitions)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateActions - validates any local actions used by the workflow exist, and are passed the inputs they declare
+func (validator *Validator) ValidateActions(definition *Definition) ValidationResult {
+	enabled := validator.getActionsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Action checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := validator.checkActions(definition.JSON)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateShellScripts - validates the syntax of any bash or sh scripts in run steps
+func (validator *Validator) ValidateShellScripts(definition *Definition) ValidationResult {
+	enabled := validator.getShellCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Shell checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := validator.checkShellScripts(definition)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateDeprecations - checks for deprecated workflow commands, runner labels and action versions. Findings are
+// reported as warnings unless the check is configured to be strict.
+func (validator *Validator) ValidateDeprecations(definition *Definition) ValidationResult {
+	enabled := validator.getDeprecationsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Deprecation checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := validator.deprecations.checkDeprecations(definition.JSON)
+	strict := validator.getDeprecationsCheckStrict(definition)
+
+	return ValidationResult{
+		Valid:  !strict || len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// ValidateSteps - validates step ids are unique within each job, and that references to step outputs point at steps
+// which have already run
+func (validator *Validator) ValidateSteps(definition *Definition) ValidationResult {
+	enabled := validator.getStepsCheckEnabled(definition)
+	if !enabled {
+		return ValidationResult{
+			Valid:  true,
+			Errors: []string{fmt.Sprintf("Step checks disabled for %s, skipping", definition.Name)},
+		}
+	}
+
+	errors := checkSteps(definition.JSON)
+
+	return ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
 func (validator *Validator) getWorkflowSchema(workflowName string) *gojsonschema.Schema {
 	workflowConfig := validator.config.Workflows.Overrides[workflowName]
 	if workflowConfig == nil || workflowConfig.Checks.Schema.URI == "" {
@@ -129,8 +355,74 @@ func (validator *Validator) getContentCheckEnabled(definition *Definition) bool
 	})
 }
 
+func (validator *Validator) getContentCheckMode(definition *Definition) string {
+	return validator.config.GetWorkflowStringProperty(definition.Name, func(config *config.GFlowsWorkflowConfig) string {
+		return config.Checks.Content.Mode
+	})
+}
+
 func (validator *Validator) getSchemaCheckEnabled(definition *Definition) bool {
 	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
 		return config.Checks.Schema.Enabled
 	})
 }
+
+func (validator *Validator) getPermissionsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, false, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Permissions.Enabled
+	})
+}
+
+func (validator *Validator) getMatrixCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Matrix.Enabled
+	})
+}
+
+func (validator *Validator) getScheduleCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Schedule.Enabled
+	})
+}
+
+func (validator *Validator) getInputsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Inputs.Enabled
+	})
+}
+
+func (validator *Validator) getCallsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Calls.Enabled
+	})
+}
+
+func (validator *Validator) getActionsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Actions.Enabled
+	})
+}
+
+func (validator *Validator) getShellCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Shell.Enabled
+	})
+}
+
+func (validator *Validator) getDeprecationsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Deprecations.Enabled
+	})
+}
+
+func (validator *Validator) getDeprecationsCheckStrict(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, false, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Deprecations.Strict
+	})
+}
+
+func (validator *Validator) getStepsCheckEnabled(definition *Definition) bool {
+	return validator.config.GetWorkflowBoolProperty(definition.Name, true, func(config *config.GFlowsWorkflowConfig) *bool {
+		return config.Checks.Steps.Enabled
+	})
+}
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -179,3 +180,619 @@ func TestValidateSchemaEnabledFlags(t *testing.T) {
 		assert.Equal(t, scenario.expectedResult, result)
 	}
 }
+
+func TestValidatePermissions(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  defaults:",
+		"    checks:",
+		"      permissions:",
+		"        enabled: true",
+		"        max:",
+		"          contents: read",
+		"  overrides:",
+		"    release:",
+		"      checks:",
+		"        permissions:",
+		"          max:",
+		"            contents: write",
+		"            packages: write",
+	}, "\n")
+
+	scenarios := []struct {
+		description    string
+		workflowName   string
+		workflow       string
+		expectedResult ValidationResult
+	}{
+		{
+			description:  "workflow permissions",
+			workflowName: "test",
+			workflow: strings.Join([]string{
+				"permissions:",
+				"  contents: read",
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+			}, "\n"),
+			expectedResult: ValidationResult{Valid: true, Errors: []string{}},
+		},
+		{
+			description:  "job permissions",
+			workflowName: "test",
+			workflow: strings.Join([]string{
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+				"    permissions: {}",
+			}, "\n"),
+			expectedResult: ValidationResult{Valid: true, Errors: []string{}},
+		},
+		{
+			description:  "missing permissions",
+			workflowName: "test",
+			workflow: strings.Join([]string{
+				"jobs:",
+				"  build:",
+				"    runs-on: ubuntu-latest",
+				"    permissions:",
+				"      contents: read",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+			}, "\n"),
+			expectedResult: ValidationResult{
+				Valid:  false,
+				Errors: []string{"jobs.test: permissions is required (set for the job or the workflow)"},
+			},
+		},
+		{
+			description:  "excessive permissions",
+			workflowName: "test",
+			workflow: strings.Join([]string{
+				"permissions:",
+				"  contents: write",
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+				"    permissions: read-all",
+			}, "\n"),
+			expectedResult: ValidationResult{
+				Valid: false,
+				Errors: []string{
+					"permissions.contents: write exceeds the maximum permitted access (read)",
+					"jobs.test.permissions.actions: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.attestations: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.checks: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.deployments: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.discussions: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.id-token: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.issues: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.packages: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.pages: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.pull-requests: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.repository-projects: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.security-events: read exceeds the maximum permitted access (none)",
+					"jobs.test.permissions.statuses: read exceeds the maximum permitted access (none)",
+				},
+			},
+		},
+		{
+			description:  "overridden maximum",
+			workflowName: "release",
+			workflow: strings.Join([]string{
+				"permissions:",
+				"  contents: write",
+				"  packages: write",
+				"jobs:",
+				"  release:",
+				"    runs-on: ubuntu-latest",
+			}, "\n"),
+			expectedResult: ValidationResult{Valid: true, Errors: []string{}},
+		},
+		{
+			description:  "invalid access level",
+			workflowName: "test",
+			workflow: strings.Join([]string{
+				"permissions:",
+				"  contents: admin",
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+			}, "\n"),
+			expectedResult: ValidationResult{
+				Valid:  false,
+				Errors: []string{"permissions: unexpected access level admin for contents (expected read, write or none)"},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		_, validator, _ := setupValidator("", config)
+		definition := newTestWorkflowDefinition(scenario.workflowName, scenario.workflow)
+		result := validator.ValidatePermissions(definition)
+		assert.Equal(t, scenario.expectedResult, result, "Unexpected result in scenario %q", scenario.description)
+	}
+}
+
+func TestValidatePermissionsDisabledByDefault(t *testing.T) {
+	_, validator, definition := setupValidator(fixtures.ExampleWorkflow("test.jsonnet"), "")
+
+	result := validator.ValidatePermissions(definition)
+
+	assert.Equal(t, ValidationResult{Valid: true, Errors: []string{}}, result)
+}
+
+func TestValidateContentSemanticMode(t *testing.T) {
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  defaults:",
+		"    checks:",
+		"      content:",
+		"        mode: semantic",
+	}, "\n")
+
+	scenarios := []struct {
+		description    string
+		actualContent  string
+		expectedResult ValidationResult
+	}{
+		{
+			description: "formatting changes",
+			actualContent: strings.Join([]string{
+				"# A comment added by hand",
+				"on:",
+				"  push:",
+				"    branches: [develop]",
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+				"    steps:",
+				"      - run: 'echo Hello, World!'",
+			}, "\n"),
+			expectedResult: ValidationResult{
+				Valid:  true,
+				Errors: []string{`Content for "test" differs only in formatting (.github/workflows/test.yml)`},
+			},
+		},
+		{
+			description: "changed workflow",
+			actualContent: strings.Join([]string{
+				"on:",
+				"  push:",
+				"    branches: [main]",
+				"jobs:",
+				"  test:",
+				"    runs-on: ubuntu-latest",
+				"    steps:",
+				"      - run: 'echo Hello, World!'",
+			}, "\n"),
+			expectedResult: ValidationResult{
+				Valid:  false,
+				Errors: []string{`Content is out of date for "test" (.github/workflows/test.yml)`},
+			},
+		},
+		{
+			description:   "invalid yaml",
+			actualContent: "&",
+			expectedResult: ValidationResult{
+				Valid:  false,
+				Errors: []string{`Content is out of date for "test" (.github/workflows/test.yml)`},
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		fs, validator, definition := setupValidator(fixtures.ExampleWorkflow("test.jsonnet"), config)
+		fs.WriteFile(definition.Destination, []byte(scenario.actualContent), 0644)
+
+		result := validator.ValidateContent(definition)
+
+		assert.Equal(t, scenario.expectedResult.Valid, result.Valid, "Unexpected result in scenario %q", scenario.description)
+		assert.Equal(t, scenario.expectedResult.Errors, result.Errors, "Unexpected errors in scenario %q", scenario.description)
+	}
+}
+
+func TestValidateContentHandEdited(t *testing.T) {
+	fs, validator, definition := setupValidator(fixtures.ExampleWorkflow("test.jsonnet"), "")
+
+	editedContent := strings.Replace(definition.Content, "echo Hello, World!", "echo Goodbye, World!", 1)
+	fs.WriteFile(definition.Destination, []byte(editedContent), 0644)
+	result := validator.ValidateContent(definition)
+
+	assert.False(t, result.Valid)
+	assert.True(t, result.HandEdited)
+	assert.Equal(t, []string{"Workflow \"test\" has been modified since it was generated (.github/workflows/test.yml)"}, result.Errors)
+}
+
+func TestValidatorIsHandEdited(t *testing.T) {
+	semanticConfig := "templates:\n  engine: ytt\nworkflows:\n  defaults:\n    checks:\n      content:\n        mode: semantic\n"
+	for _, config := range []string{"templates:\n  engine: ytt\n", semanticConfig} {
+		_, validator, definition := setupValidator(fixtures.ExampleWorkflow("test.jsonnet"), config)
+		reformattedContent := definition.Content + "\n"
+		editedContent := strings.Replace(definition.Content, "echo Hello, World!", "echo Goodbye, World!", 1)
+
+		// formatting changes are only ignored in semantic mode
+		assert.Equal(t, config != semanticConfig, validator.IsHandEdited(definition, reformattedContent))
+		assert.True(t, validator.IsHandEdited(definition, editedContent))
+		assert.False(t, validator.IsHandEdited(definition, definition.Content))
+	}
+}
+
+func TestIsHandEdited(t *testing.T) {
+	workflow := fixtures.ExampleWorkflow("test.jsonnet")
+	assert.False(t, IsHandEdited(workflow))
+	assert.True(t, IsHandEdited(workflow+"# a comment\n"))
+	assert.False(t, IsHandEdited("# File generated by gflows, do not modify\n# Source: test.jsonnet\nfoo: bar\n"))
+	assert.False(t, IsHandEdited("foo: bar\n"))
+}
+
+func TestValidateMatrices(t *testing.T) {
+	values := []string{}
+	for i := 0; i < 17; i++ {
+		values = append(values, fmt.Sprintf("%d", i))
+	}
+	workflow := strings.Join([]string{
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+		"    strategy:",
+		"      matrix:",
+		"        os: [ubuntu, windows]",
+		"        exclude:",
+		"        - os: macos",
+		"  large:",
+		"    runs-on: ubuntu-latest",
+		"    strategy:",
+		"      matrix:",
+		"        foo: [" + strings.Join(values, ", ") + "]",
+		"        bar: [" + strings.Join(values, ", ") + "]",
+		"  invalid:",
+		"    runs-on: ubuntu-latest",
+		"    strategy:",
+		"      matrix:",
+		"        os: [ubuntu]",
+		"        include: [ubuntu]",
+		"  dynamic:",
+		"    runs-on: ubuntu-latest",
+		"    strategy:",
+		"      matrix: ${{ fromJSON(needs.setup.outputs.matrix) }}",
+	}, "\n")
+	_, validator, _ := setupValidator("", "")
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateMatrices(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			"jobs.invalid.strategy.matrix: unexpected include entry: ubuntu (expected a map)",
+			"jobs.large.strategy.matrix: matrix generates 289 jobs, exceeding the limit of 256",
+			"jobs.test.strategy.matrix.exclude: {os: macos} doesn't match any combination",
+		},
+	}, result)
+}
+
+func TestValidateSchedules(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on":`,
+		"  schedule:",
+		"  - cron: '0 2 * * *'",
+		"  - cron: '*/2 * * * *'",
+		"  - cron: '0 2 * *'",
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+	}, "\n")
+	_, validator, _ := setupValidator("", "")
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateSchedules(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`on.schedule[1].cron: "*/2 * * * *" runs as often as every 2 minutes, but the shortest interval GitHub supports is 5 minutes`,
+			`on.schedule[2].cron: "0 2 * *" is invalid: expected 5 fields but found 4`,
+		},
+	}, result)
+}
+
+func TestValidateInputs(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on":`,
+		"  workflow_dispatch:",
+		"    inputs:",
+		"      environment:",
+		"        type: choice",
+		"        default: production",
+		"        options: [staging, prod]",
+		"      dry-run:",
+		"        type: boolean",
+		"        default: 'false'",
+		"      level:",
+		"        type: choice",
+		"  workflow_call:",
+		"    inputs:",
+		"      version:",
+		"        type: string",
+		"      count:",
+		"        default: 3",
+		"    secrets:",
+		"      token:",
+		"        required: 'yes'",
+		"    outputs:",
+		"      artifact:",
+		"        value: ${{ jobs.build.outputs.artifact }}",
+		"      report:",
+		"        value: ${{ jobs.test.outputs.report }}",
+		"jobs:",
+		"  build:",
+		"    runs-on: ubuntu-latest",
+		"    if: inputs.dry-run != true",
+		"    outputs:",
+		"      path: ${{ steps.build.outputs.path }}",
+		"    steps:",
+		"    - run: echo ${{ inputs.version }} ${{ github.event.inputs.tag }}",
+	}, "\n")
+	_, validator, _ := setupValidator("", "")
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateInputs(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`on.workflow_dispatch.inputs.dry-run.default: "false" is not a valid boolean value`,
+			`on.workflow_dispatch.inputs.environment.default: "production" is not a valid choice value`,
+			"on.workflow_dispatch.inputs.level.options: choice inputs require at least one option",
+			"on.workflow_call.inputs.count.type: type is required",
+			"on.workflow_call.secrets.token.required: expected a boolean but found yes",
+			`on.workflow_call.outputs.artifact.value: job "build" does not declare output "artifact"`,
+			`on.workflow_call.outputs.report.value: job "test" does not exist`,
+			`jobs.build.steps[0].run: input "tag" is not declared by workflow_dispatch or workflow_call`,
+		},
+	}, result)
+}
+
+func TestValidateWorkflowCalls(t *testing.T) {
+	build := newTestWorkflowDefinition("build", strings.Join([]string{
+		`"on":`,
+		"  workflow_call:",
+		"    inputs:",
+		"      version:",
+		"        type: string",
+		"        required: true",
+		"      debug:",
+		"        type: boolean",
+		"    secrets:",
+		"      token:",
+		"        required: true",
+		"jobs:",
+		"  build:",
+		"    runs-on: ubuntu-latest",
+	}, "\n"))
+	deploy := newTestWorkflowDefinition("deploy", strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  build:",
+		"    uses: ./.github/workflows/build.yml",
+		"    with:",
+		"      debug: 'yes'",
+		"      target: production",
+		"    secrets:",
+		"      password: ${{ secrets.PASSWORD }}",
+		"  build-with-expressions:",
+		"    uses: ./.github/workflows/build.yml",
+		"    with:",
+		"      version: ${{ github.sha }}",
+		"      debug: ${{ github.event_name == 'push' }}",
+		"    secrets: inherit",
+		"  test:",
+		"    uses: ./.github/workflows/test.yml",
+		"  lint:",
+		"    uses: ./.github/workflows/lint.yml",
+		"  publish:",
+		"    uses: octo-org/example-repo/.github/workflows/publish.yml@main",
+	}, "\n"))
+	test := newTestWorkflowDefinition("test", `"on": push`)
+	fs, validator, _ := setupValidator("", "")
+
+	result := validator.ValidateWorkflowCalls(deploy, []*Definition{build, deploy, test})
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`jobs.build.with.debug: expected a boolean but found "yes"`,
+			`jobs.build.with.target: input "target" is not declared by ./.github/workflows/build.yml`,
+			`jobs.build.with: required input "version" for ./.github/workflows/build.yml is missing`,
+			`jobs.build.secrets.password: secret "password" is not declared by ./.github/workflows/build.yml`,
+			`jobs.build.secrets: required secret "token" for ./.github/workflows/build.yml is missing`,
+			"jobs.lint.uses: workflow ./.github/workflows/lint.yml does not exist",
+			"jobs.test.uses: ./.github/workflows/test.yml does not declare on.workflow_call",
+		},
+	}, result)
+
+	// workflows which aren't generated by gflows are read from the repository
+	fs.WriteFile(".github/workflows/lint.yml", []byte("on: workflow_call\njobs: {}\n"), 0644)
+	result = validator.ValidateWorkflowCalls(newTestWorkflowDefinition("ci", strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  lint:",
+		"    uses: ./.github/workflows/lint.yml",
+	}, "\n")), []*Definition{})
+	assert.Equal(t, ValidationResult{Valid: true, Errors: []string{}}, result)
+
+	// workflows whose templates failed to generate them are reported as having errors
+	broken := &Definition{
+		Name:        "build",
+		Destination: ".github/workflows/build.yml",
+		Status:      ValidationResult{Valid: false, Errors: []string{"template error"}},
+	}
+	result = validator.ValidateWorkflowCalls(deploy, []*Definition{broken, deploy, test})
+	assert.Contains(t, result.Errors, "jobs.build.uses: workflow ./.github/workflows/build.yml has errors")
+	assert.NotContains(t, result.Errors, "jobs.build.uses: ./.github/workflows/build.yml does not declare on.workflow_call")
+}
+
+func TestValidateActions(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  test:",
+		"    runs-on: ubuntu-latest",
+		"    steps:",
+		"    - uses: actions/checkout@v2",
+		"    - uses: ./actions/greet",
+		"      with:",
+		"        greeting: hello",
+		"        nmae: world",
+		"    - uses: ./actions/setup",
+		"    - uses: ./actions/missing",
+	}, "\n")
+	fs, validator, _ := setupValidator("", "")
+	fs.WriteFile("actions/greet/action.yml", []byte(strings.Join([]string{
+		"inputs:",
+		"  greeting:",
+		"    required: true",
+		"  name:",
+		"    required: true",
+		"  punctuation:",
+		"    required: true",
+		"    default: '!'",
+	}, "\n")), 0644)
+	fs.WriteFile("actions/setup/action.yaml", []byte("runs:\n  using: composite\n  steps: []\n"), 0644)
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateActions(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`jobs.test.steps[1].with.nmae: input "nmae" is not declared by ./actions/greet`,
+			`jobs.test.steps[1].with: required input "name" for ./actions/greet is missing`,
+			"jobs.test.steps[3].uses: action ./actions/missing does not exist (expected action.yml or action.yaml)",
+		},
+	}, result)
+}
+
+func TestValidateShellScripts(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  linux:",
+		"    runs-on: ubuntu-latest",
+		"    steps:",
+		"    - run: echo ${{ github.sha }} | tee sha.txt",
+		"    - name: Build",
+		"      run: |",
+		"        if [ -f Makefile ]; then",
+		"          make build",
+		"    - run: diff <(ls foo) <(ls bar)",
+		"      shell: sh",
+		"  windows:",
+		"    runs-on: windows-latest",
+		"    steps:",
+		"    - run: if ($true) {",
+		"    - run: echo $(",
+		"      shell: bash",
+	}, "\n")
+	_, validator, _ := setupValidator("", "")
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateShellScripts(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`jobs.linux.steps[1].run: if statement must end with "fi" (step "Build", line 1)`,
+			"jobs.linux.steps[2].run: < must be followed by a word (line 1)",
+			`jobs.windows.steps[1].run: reached EOF without matching ( with ) (line 1)`,
+		},
+	}, result)
+}
+
+func TestMaskExpressions(t *testing.T) {
+	assert.Equal(t, "echo xxxxxxxxxxxxxxxxx\necho xxxxxxxxxx\nxxxxxx",
+		maskExpressions("echo ${{ github.sha }}\necho ${{ foo ||\nbar }}"))
+}
+
+func TestValidateDeprecations(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  test:",
+		"    runs-on: [self-hosted, ubuntu-18.04]",
+		"    steps:",
+		"    - uses: actions/checkout@v2.3.4",
+		"    - uses: actions/checkout@v4",
+		"    - run: |",
+		`        echo "::set-output name=sha::$(git rev-parse HEAD)"`,
+		`        echo "::add-path::/usr/local/bin"`,
+		"    - run: echo $GITHUB_OUTPUT",
+	}, "\n")
+	expectedErrors := []string{
+		"jobs.test.runs-on: the ubuntu-18.04 runner has been retired, use ubuntu-latest instead",
+		"jobs.test.steps[0].uses: actions/checkout@v2.3.4 runs on a deprecated version of Node, use actions/checkout@v4 instead",
+		"jobs.test.steps[2].run: the set-output command is deprecated, write to $GITHUB_OUTPUT instead",
+		"jobs.test.steps[2].run: the add-path command is deprecated, write to $GITHUB_PATH instead",
+	}
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	_, validator, _ := setupValidator("", "")
+	result := validator.ValidateDeprecations(definition)
+	assert.Equal(t, ValidationResult{Valid: true, Errors: expectedErrors}, result)
+
+	config := strings.Join([]string{
+		"templates:",
+		"  engine: ytt",
+		"workflows:",
+		"  defaults:",
+		"    checks:",
+		"      deprecations:",
+		"        strict: true",
+	}, "\n")
+	_, validator, _ = setupValidator("", config)
+	result = validator.ValidateDeprecations(definition)
+	assert.Equal(t, ValidationResult{Valid: false, Errors: expectedErrors}, result)
+}
+
+func TestValidateSteps(t *testing.T) {
+	workflow := strings.Join([]string{
+		`"on": push`,
+		"jobs:",
+		"  build:",
+		"    runs-on: ubuntu-latest",
+		"    outputs:",
+		"      version: ${{ steps.version.outputs.version }}",
+		"      artifact: ${{ steps.upload.outputs.path }}",
+		"    steps:",
+		"    - id: version",
+		"      run: echo version=1.0 >> $GITHUB_OUTPUT",
+		"    - id: build",
+		"      if: steps.version.outputs.version != ''",
+		"      run: echo ${{ steps.test.outputs.result }}",
+		"    - id: test",
+		"      run: echo result=ok >> $GITHUB_OUTPUT",
+		"      env:",
+		"        VERSION: ${{ steps.versoin.outputs.version }}",
+		"    - id: version",
+		"      run: echo ${{ steps.version.outputs.version }}",
+	}, "\n")
+	_, validator, _ := setupValidator("", "")
+	definition := newTestWorkflowDefinition("test", workflow)
+
+	result := validator.ValidateSteps(definition)
+
+	assert.Equal(t, ValidationResult{
+		Valid: false,
+		Errors: []string{
+			`jobs.build.steps[3].id: duplicate step id "version" (already used by steps[0])`,
+			`jobs.build.steps[1].run: step "test" does not run before this step`,
+			`jobs.build.steps[2].env.VERSION: step "versoin" does not exist`,
+			`jobs.build.outputs.artifact: step "upload" does not exist`,
+		},
+	}, result)
+}
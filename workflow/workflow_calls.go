@@ -0,0 +1,132 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jbrunton/gflows/yamlutil"
+	"github.com/thoas/go-funk"
+)
+
+// checkWorkflowCalls - returns errors for any jobs which call local reusable workflows that don't exist, don't
+// declare on.workflow_call, or which are passed inputs and secrets the called workflow doesn't accept
+func (validator *Validator) checkWorkflowCalls(definition *Definition, definitions []*Definition) []string {
+	errors := []string{}
+	jobs := getJobs(definition.JSON)
+	for _, jobName := range sortedKeys(jobs) {
+		job, _ := jobs[jobName].(map[string]interface{})
+		uses, _ := job["uses"].(string)
+		if !strings.HasPrefix(uses, "./") {
+			continue
+		}
+		path := fmt.Sprintf("jobs.%s", jobName)
+
+		calledJSON, err := validator.loadCalledWorkflow(uses, definitions)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s.uses: %s", path, err))
+			continue
+		}
+		if !funk.ContainsString(getTriggers(calledJSON), "workflow_call") {
+			errors = append(errors, fmt.Sprintf("%s.uses: %s does not declare on.workflow_call", path, uses))
+			continue
+		}
+		on, _ := getOn(calledJSON).(map[string]interface{})
+		callMap, _ := on["workflow_call"].(map[string]interface{})
+
+		inputs, _ := callMap["inputs"].(map[string]interface{})
+		with, _ := job["with"].(map[string]interface{})
+		for _, name := range sortedKeys(with) {
+			declaration, ok := inputs[name]
+			if !ok {
+				errors = append(errors, fmt.Sprintf("%s.with.%s: input %q is not declared by %s", path, name, name, uses))
+				continue
+			}
+			input, _ := declaration.(map[string]interface{})
+			inputType, _ := input["type"].(string)
+			value := with[name]
+			if isExpression(value) || inputType == "" {
+				continue
+			}
+			if !isValidInputValue(inputType, value, nil) {
+				errors = append(errors, fmt.Sprintf("%s.with.%s: expected a %s but found %s", path, name, inputType, formatInputValue(value)))
+			}
+		}
+		for _, name := range sortedKeys(inputs) {
+			input, _ := inputs[name].(map[string]interface{})
+			_, hasDefault := input["default"]
+			if input["required"] == true && !hasDefault {
+				if _, ok := with[name]; !ok {
+					errors = append(errors, fmt.Sprintf("%s.with: required input %q for %s is missing", path, name, uses))
+				}
+			}
+		}
+
+		if job["secrets"] == "inherit" {
+			continue
+		}
+		declaredSecrets, _ := callMap["secrets"].(map[string]interface{})
+		secrets, _ := job["secrets"].(map[string]interface{})
+		for _, name := range sortedKeys(secrets) {
+			if _, ok := declaredSecrets[name]; !ok {
+				errors = append(errors, fmt.Sprintf("%s.secrets.%s: secret %q is not declared by %s", path, name, name, uses))
+			}
+		}
+		for _, name := range sortedKeys(declaredSecrets) {
+			secret, _ := declaredSecrets[name].(map[string]interface{})
+			if secret["required"] == true {
+				if _, ok := secrets[name]; !ok {
+					errors = append(errors, fmt.Sprintf("%s.secrets: required secret %q for %s is missing", path, name, uses))
+				}
+			}
+		}
+	}
+	return errors
+}
+
+// loadCalledWorkflow - returns the JSON for a local reusable workflow, preferring the generated definition if there
+// is one (and returning an error if its template failed to generate it), and otherwise reading the workflow from the
+// repository
+func (validator *Validator) loadCalledWorkflow(uses string, definitions []*Definition) (interface{}, error) {
+	path := validator.resolveRepoPath(uses)
+	for _, definition := range definitions {
+		if filepath.Clean(definition.Destination) == path {
+			if !definition.Status.Valid || definition.JSON == nil {
+				return nil, fmt.Errorf("workflow %s has errors", uses)
+			}
+			return definition.JSON, nil
+		}
+	}
+
+	exists, err := validator.fs.Exists(path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("workflow %s does not exist", uses)
+	}
+	data, err := validator.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := yamlutil.NormalizeWorkflow(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", uses, err)
+	}
+	json, err := yamlutil.YamlToJson(content)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %s", uses, err)
+	}
+	return json, nil
+}
+
+// resolveRepoPath - resolves a path relative to the root of the repository, i.e. the parent of the GitHub directory
+func (validator *Validator) resolveRepoPath(path string) string {
+	repoDir := filepath.Dir(filepath.Clean(validator.githubDir))
+	return filepath.Join(repoDir, path)
+}
+
+func isExpression(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.Contains(s, "${{")
+}
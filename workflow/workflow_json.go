@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"sort"
+)
+
+// getJobs - returns the jobs in the JSON for a workflow, keyed by job name
+func getJobs(workflowJSON interface{}) map[string]interface{} {
+	workflowMap, ok := workflowJSON.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	jobs, ok := workflowMap["jobs"].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return jobs
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch m := m.(type) {
+	case map[string]interface{}:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getSteps - returns the steps for the given job
+func getSteps(job interface{}) []interface{} {
+	jobMap, ok := job.(map[string]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+	steps, ok := jobMap["steps"].([]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+	return steps
+}
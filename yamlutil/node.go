@@ -0,0 +1,138 @@
+package yamlutil
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// yaml11Bools - plain scalars which YAML 1.1 parsers (including ytt's) treat as booleans, but which GitHub treats as
+// strings (e.g. the "on" key)
+var yaml11Bools = []string{"y", "yes", "n", "no", "on", "off"}
+
+// ParseWorkflowNode - parses a workflow into a YAML document node, which preserves its comments and the order of its
+// keys. Strings which YAML 1.1 parsers would treat as booleans are quoted, so that they remain strings. Comments above
+// sequence items are moved from their first key to the item, so that they're encoded above the item as well.
+func ParseWorkflowNode(content string) (*yamlv3.Node, error) {
+	var document yamlv3.Node
+	err := yamlv3.Unmarshal([]byte(content), &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 {
+		// empty file
+		document = yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode, Tag: "!!map"}}}
+	}
+	QuoteYaml11Bools(&document)
+	moveItemComments(&document)
+	return &document, nil
+}
+
+// EncodeNode - encodes a YAML node, using the same indentation as workflows generated by gflows
+func EncodeNode(node *yamlv3.Node) (string, error) {
+	var buffer bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// GetMappingValue - returns the value for the given key in a mapping node, or nil if there isn't one
+func GetMappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// QuoteYaml11Bools - quotes plain strings in the node (and its descendants) which YAML 1.1 parsers would treat as
+// booleans
+func QuoteYaml11Bools(node *yamlv3.Node) {
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!str" && node.Style == 0 {
+		for _, value := range yaml11Bools {
+			if strings.ToLower(node.Value) == value {
+				node.Style = yamlv3.DoubleQuotedStyle
+			}
+		}
+	}
+	for _, child := range node.Content {
+		QuoteYaml11Bools(child)
+	}
+}
+
+// moveItemComments - moves the head comments of the first key in each mapping in a sequence to the mapping. The YAML
+// parser attaches comments above an item to its first key, but they're then encoded after the "- " indicator.
+func moveItemComments(node *yamlv3.Node) {
+	if node.Kind == yamlv3.SequenceNode {
+		for _, item := range node.Content {
+			if item.Kind == yamlv3.MappingNode && len(item.Content) > 0 && item.Content[0].HeadComment != "" {
+				item.HeadComment = joinComments(item.HeadComment, item.Content[0].HeadComment)
+				item.Content[0].HeadComment = ""
+			}
+		}
+	}
+	for _, child := range node.Content {
+		moveItemComments(child)
+	}
+}
+
+func joinComments(comments ...string) string {
+	nonEmpty := []string{}
+	for _, comment := range comments {
+		if comment != "" {
+			nonEmpty = append(nonEmpty, comment)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
+
+// MapComments - returns a copy of the node with each line of its comments (and those of its descendants) transformed by
+// the given function
+func MapComments(node *yamlv3.Node, mapLine func(line string) string) *yamlv3.Node {
+	result := *node
+	mapComment := func(comment string) string {
+		if comment == "" {
+			return comment
+		}
+		lines := strings.Split(comment, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = mapLine(line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	result.HeadComment = mapComment(node.HeadComment)
+	result.LineComment = mapComment(node.LineComment)
+	result.FootComment = mapComment(node.FootComment)
+	result.Content = make([]*yamlv3.Node, len(node.Content))
+	for i, child := range node.Content {
+		result.Content[i] = MapComments(child, mapLine)
+	}
+	return &result
+}
+
+// HaveSameValues - returns true if the YAML documents have the same values, ignoring formatting and comments. Values are
+// parsed as GitHub does (e.g. "on" is a string), rather than as YAML 1.1.
+func HaveSameValues(content string, otherContent string) bool {
+	var value, otherValue interface{}
+	if err := yamlv3.Unmarshal([]byte(content), &value); err != nil {
+		return false
+	}
+	if err := yamlv3.Unmarshal([]byte(otherContent), &otherValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(value, otherValue)
+}
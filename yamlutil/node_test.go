@@ -0,0 +1,51 @@
+package yamlutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWorkflowNode(t *testing.T) {
+	node, err := ParseWorkflowNode("# CI workflow\non: push\njobs:\n  test:\n    env:\n      GO111MODULE: on # enable modules\n")
+	assert.NoError(t, err)
+
+	result, err := EncodeNode(node)
+	assert.NoError(t, err)
+	assert.Equal(t, "# CI workflow\n\"on\": push\njobs:\n  test:\n    env:\n      GO111MODULE: \"on\" # enable modules\n", result)
+}
+
+func TestParseWorkflowNodeWithItemComments(t *testing.T) {
+	node, err := ParseWorkflowNode("steps:\n# check out the code\n- uses: actions/checkout@v4\n- run: make # build\n")
+	assert.NoError(t, err)
+
+	result, err := EncodeNode(node)
+	assert.NoError(t, err)
+	assert.Equal(t, "steps:\n# check out the code\n- uses: actions/checkout@v4\n- run: make # build\n", result)
+}
+
+func TestParseEmptyWorkflowNode(t *testing.T) {
+	node, err := ParseWorkflowNode("")
+	assert.NoError(t, err)
+
+	result, err := EncodeNode(node)
+	assert.NoError(t, err)
+	assert.Equal(t, "{}\n", result)
+}
+
+func TestMapComments(t *testing.T) {
+	node, _ := ParseWorkflowNode("# first\n# second\nfoo: bar # line\n")
+
+	result, _ := EncodeNode(MapComments(node, func(line string) string { return "#!" + line[1:] }))
+	original, _ := EncodeNode(node)
+
+	assert.Equal(t, "#! first\n#! second\nfoo: bar #! line\n", result)
+	assert.Equal(t, "# first\n# second\nfoo: bar # line\n", original)
+}
+
+func TestHaveSameValues(t *testing.T) {
+	assert.True(t, HaveSameValues("# comment\non: push\nenv: {FLAG: on}\n", "\"on\": push\nenv:\n  FLAG: \"on\"\n"))
+	assert.True(t, HaveSameValues("go: [1.14]\n", "go: [1.1399999999999999]\n"))
+	assert.False(t, HaveSameValues("env: {FLAG: on}\n", "env: {FLAG: true}\n"))
+	assert.False(t, HaveSameValues("foo: bar\n", "foo: [bar"))
+}
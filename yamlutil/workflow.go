@@ -5,10 +5,21 @@ import (
 )
 
 func NormalizeWorkflow(content string) (string, error) {
+	yamlData, err := ParseWorkflow(content)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := yaml.Marshal(yamlData)
+	return string(result), err
+}
+
+// ParseWorkflow - parses a workflow, preserving the order of its keys
+func ParseWorkflow(content string) (yaml.MapSlice, error) {
 	var yamlData yaml.MapSlice
 	err := yaml.Unmarshal([]byte(content), &yamlData)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	for i, item := range yamlData {
@@ -19,6 +30,5 @@ func NormalizeWorkflow(content string) (string, error) {
 		}
 	}
 
-	result, err := yaml.Marshal(yamlData)
-	return string(result), err
+	return yamlData, nil
 }